@@ -0,0 +1,89 @@
+package exportdocx
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ฟอนต์ของ <pre> และ <code> (ข้อความไทยใช้ฟอนต์ complex script ตามเดิม)
+const monospaceFont = "Courier New"
+
+// paragraph style ของ block element ที่ไม่ใช่ย่อหน้าเนื้อหาปกติ
+// <h1> ไม่อยู่ในรายการเพราะชื่อบทใช้ Heading1 อยู่แล้ว
+var blockParagraphStyles = map[atom.Atom]string{
+	atom.H2:         "Heading2",
+	atom.H3:         "Heading3",
+	atom.H4:         "Heading4",
+	atom.H5:         "Heading5",
+	atom.H6:         "Heading6",
+	atom.Blockquote: "Quote",
+	atom.Pre:        "HTMLPreformatted",
+}
+
+// withBlockStyle ใส่ style ของ block ที่ครอบ (เช่น Quote ของ <blockquote>) ให้ย่อหน้าข้างใน
+// ที่ยังไม่มี style จาก block ชั้นในกว่า (รายการใน list และตารางใช้ style ของตัวเอง)
+func withBlockStyle(segments []contentSegment, block *html.Node) []contentSegment {
+	style, ok := blockParagraphStyles[block.DataAtom]
+	if !ok {
+		return segments
+	}
+	for i := range segments {
+		segment := &segments[i]
+		if (segment.Type == "text" || segment.Type == "inline") && segment.List == nil && segment.Style == "" {
+			segment.Style = style
+		}
+	}
+	return segments
+}
+
+// restyleBodyParagraph เปลี่ยน BodyText และ BodyTextIndent ของ paragraph เป็น style
+// ย่อหน้าที่มี style อื่นอยู่แล้ว (เช่นหัวข้อหรือ <pre>) ไม่เปลี่ยน
+func restyleBodyParagraph(para *Paragraph, style string) {
+	if para.Props == nil || para.Props.PStyle == nil {
+		return
+	}
+	switch para.Props.PStyle.Val {
+	case "BodyText", "BodyTextIndent":
+		// คัดลอก PPr เพราะบาง paragraph อาจใช้ pointer ร่วมกัน
+		props := *para.Props
+		props.PStyle = &PStyle{Val: style}
+		para.Props = &props
+	}
+}
+
+// splitAtParagraphBreaks แยก inline nodes ที่ <br> ตั้งแต่สองตัวติดกัน (ข้าม whitespace ระหว่าง <br>)
+// <br> ที่ใช้แยกถูกตัดทิ้ง ส่วน <br> ตัวเดียวยังเป็น line break ภายในย่อหน้า
+func splitAtParagraphBreaks(nodes []*html.Node) [][]*html.Node {
+	var groups [][]*html.Node
+	start := 0
+	for i := 0; i < len(nodes); {
+		if !isBreak(nodes[i]) {
+			i++
+			continue
+		}
+		breaks, end := 0, i
+		for j := i; j < len(nodes); j++ {
+			if isBreak(nodes[j]) {
+				breaks++
+				end = j + 1
+			} else if nodes[j].Type != html.TextNode || strings.TrimSpace(nodes[j].Data) != "" {
+				break
+			}
+		}
+		if breaks >= 2 {
+			groups = append(groups, nodes[start:i])
+			start = end
+		}
+		i = end
+	}
+	if len(nodes) > 0 {
+		groups = append(groups, nodes[start:])
+	}
+	return groups
+}
+
+func isBreak(n *html.Node) bool {
+	return n.Type == html.ElementNode && n.DataAtom == atom.Br
+}
@@ -0,0 +1,79 @@
+package exportdocx
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+func TestExportBlockElements(t *testing.T) {
+	chapters := []ChapterData{{ID: "1", Chapter: "บทที่ 1", Body: `<h2>ส่วนที่ 1</h2><h6>ย่อย</h6>` +
+		`<blockquote><p>คำพูด</p><h3>หัวข้อในคำพูด</h3></blockquote><blockquote>สั้น</blockquote>` +
+		"<pre><code>func main() {\n\tprintln(\"hi\")\n}\n</code></pre>" +
+		`<p>ใช้ <code>go run</code></p>` +
+		`<div class="indent-a">บรรทัดแรก<br>ต่อ<br> <br>ย่อหน้าใหม่</div>` +
+		`<div style="text-align: center">หลวม<p>ย่อหน้า</p>ท้าย</div>`}}
+
+	var buf bytes.Buffer
+	if err := Export(context.Background(), chapters, &buf, Options{}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	files := readZipFiles(t, buf.Bytes())
+	doc := compactXML(files["word/document.xml"])
+
+	for _, want := range []string{
+		`<w:pStylew:val="Heading2"></w:pStyle></w:pPr><w:r><w:txml:space="preserve">ส่วนที่1</w:t>`,
+		`<w:pStylew:val="Heading6"></w:pStyle></w:pPr><w:r><w:txml:space="preserve">ย่อย</w:t>`,
+		// ย่อหน้าใน <blockquote> ใช้ Quote แต่หัวข้อยังเป็นหัวข้อ
+		`<w:pStylew:val="Quote"></w:pStyle></w:pPr><w:r><w:txml:space="preserve">คำพูด</w:t>`,
+		`<w:pStylew:val="Heading3"></w:pStyle></w:pPr><w:r><w:txml:space="preserve">หัวข้อในคำพูด</w:t>`,
+		`<w:pStylew:val="Quote"></w:pStyle></w:pPr><w:r><w:txml:space="preserve">สั้น</w:t>`,
+		// <pre> เก็บการขึ้นบรรทัดและ tab แต่ตัดบรรทัดว่างท้ายสุด
+		`<w:pStylew:val="HTMLPreformatted"></w:pStyle></w:pPr><w:r><w:txml:space="preserve">funcmain(){</w:t></w:r><w:r><w:br></w:br></w:r><w:r><w:tab></w:tab></w:r>`,
+		`<w:txml:space="preserve">}</w:t></w:r></w:p>`,
+		`<w:r><w:rPr><w:rStylew:val="HTMLCode"></w:rStyle></w:rPr><w:txml:space="preserve">gorun</w:t>`,
+		// <br><br> ใน <div> แยกย่อหน้า และใช้ class ของ <div>
+		`<w:pStylew:val="BodyTextIndent"></w:pStyle></w:pPr><w:r><w:txml:space="preserve">บรรทัดแรก</w:t></w:r><w:r><w:br></w:br></w:r><w:r><w:txml:space="preserve">ต่อ</w:t></w:r></w:p>`,
+		`<w:pStylew:val="BodyTextIndent"></w:pStyle></w:pPr><w:r><w:txml:space="preserve">ย่อหน้าใหม่</w:t>`,
+		// ข้อความหลวมใน <div> เป็นย่อหน้าของตัวเองตาม style ของ <div>
+		`<w:pStylew:val="BodyText"></w:pStyle><w:jcw:val="center"></w:jc></w:pPr><w:r><w:txml:space="preserve">หลวม</w:t>`,
+		`<w:pStylew:val="BodyText"></w:pStyle><w:jcw:val="center"></w:jc></w:pPr><w:r><w:txml:space="preserve">ท้าย</w:t>`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("document.xml missing %s", want)
+		}
+	}
+	if strings.Contains(doc, "<w:rPr></w:rPr>") {
+		t.Errorf("document.xml should not contain empty rPr")
+	}
+
+	styles := files["word/styles.xml"]
+	for _, want := range []string{`w:styleId="Heading2"`, `w:styleId="Heading6"`, `w:styleId="Quote"`, `w:styleId="HTMLPreformatted"`, `w:styleId="HTMLCode"`} {
+		if !strings.Contains(styles, want) {
+			t.Errorf("styles.xml missing %s", want)
+		}
+	}
+	if !strings.Contains(compactXML(styles), `<w:outlineLvlw:val="4"/></w:pPr><w:rPr><w:b/><w:bCs/><w:szw:val="22"/>`) {
+		t.Errorf("Heading5 should have outline level 4")
+	}
+}
+
+func TestSplitAtParagraphBreaks(t *testing.T) {
+	body := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	nodes, err := html.ParseFragment(strings.NewReader("a<br>b<br>\n<br><br>c<br>"), body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, group := range splitAtParagraphBreaks(nodes) {
+		got = append(got, strings.TrimSpace(textContentOf(group)))
+	}
+	if want := []string{"ab", "c"}; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("splitAtParagraphBreaks = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+package exportdocx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// imageCache เก็บรูปที่ดาวน์โหลดแล้วลงดิสก์ เพื่อใช้ซ้ำระหว่างการ export หลายครั้ง
+// แต่ละ URL เก็บเป็นสองไฟล์: <hash>.bin (ข้อมูลรูป) และ <hash>.json (metadata)
+type imageCache struct {
+	dir string
+}
+
+// cacheEntry คือ metadata ของรูปที่ cache ไว้
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	ContentType  string    `json:"content_type"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	StoredAt     time.Time `json:"stored_at"`
+	SHA256       string    `json:"sha256"` // hash ของข้อมูลใน .bin ใช้ตรวจว่า .bin กับ .json เป็นคู่เดียวกัน
+}
+
+func newImageCache(dir string) *imageCache {
+	return &imageCache{dir: dir}
+}
+
+func (c *imageCache) paths(url string) (dataPath, metaPath string) {
+	sum := sha256.Sum256([]byte(url))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, key+".bin"), filepath.Join(c.dir, key+".json")
+}
+
+// load อ่านรูปและ metadata ของ URL (ok = false ถ้าไม่มีใน cache, ไฟล์เสีย หรือข้อมูลไม่ตรงกับ metadata)
+func (c *imageCache) load(url string) (entry cacheEntry, data []byte, ok bool) {
+	dataPath, metaPath := c.paths(url)
+
+	meta, err := os.ReadFile(metaPath)
+	if err != nil {
+		return cacheEntry{}, nil, false
+	}
+	if err := json.Unmarshal(meta, &entry); err != nil || entry.URL != url {
+		return cacheEntry{}, nil, false
+	}
+	data, err = os.ReadFile(dataPath)
+	if err != nil || entry.SHA256 != dataHash(data) {
+		return cacheEntry{}, nil, false
+	}
+	return entry, data, true
+}
+
+// store บันทึกรูปและ metadata โดยเขียนไฟล์ชั่วคราวก่อนแล้ว rename
+// เพื่อไม่ให้ export ที่ทำงานพร้อมกันอ่านไฟล์ที่เขียนไม่ครบ
+// metadata เขียนทีหลังพร้อม hash ของข้อมูล ถ้าสองไฟล์ไม่ใช่คู่เดียวกัน (crash หรือเขียนพร้อมกัน) load จะถือว่าไม่มีใน cache
+func (c *imageCache) store(entry cacheEntry, data []byte) error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	dataPath, metaPath := c.paths(entry.URL)
+	entry.SHA256 = dataHash(data)
+
+	meta, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := c.writeFile(dataPath, data); err != nil {
+		return err
+	}
+	return c.writeFile(metaPath, meta)
+}
+
+func dataHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *imageCache) writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(c.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,148 @@
+package exportdocx
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestExportUsesDiskCache(t *testing.T) {
+	data := pngBytes(t, 12, 8)
+	var downloads, revalidations int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&revalidations, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(&downloads, 1)
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	chapters := []ChapterData{{ID: "1", Chapter: "1", Body: `<p><img src="` + server.URL + `/cover.png"></p>`}}
+	cacheDir := t.TempDir()
+
+	export := func(opts Options) (Summary, map[string]string) {
+		t.Helper()
+		var buf bytes.Buffer
+		exporter := New(opts)
+		if err := exporter.Export(context.Background(), chapters, &buf); err != nil {
+			t.Fatalf("Export: %v", err)
+		}
+		return exporter.Summary(), readZipFiles(t, buf.Bytes())
+	}
+
+	// ครั้งแรก: ยังไม่มีใน cache
+	summary, _ := export(Options{CacheDir: cacheDir})
+	if summary.CacheHits != 0 || summary.CacheMisses != 1 {
+		t.Errorf("first run: hits=%d misses=%d, want 0/1", summary.CacheHits, summary.CacheMisses)
+	}
+
+	// ครั้งที่สอง: revalidate ด้วย ETag แล้วได้ 304
+	summary, _ = export(Options{CacheDir: cacheDir})
+	if summary.CacheHits != 1 || summary.CacheMisses != 0 {
+		t.Errorf("second run: hits=%d misses=%d, want 1/0", summary.CacheHits, summary.CacheMisses)
+	}
+	if got := atomic.LoadInt32(&revalidations); got != 1 {
+		t.Errorf("conditional requests = %d, want 1", got)
+	}
+
+	// offline: ไม่เรียก network เลย
+	server.Close()
+	summary, files := export(Options{CacheDir: cacheDir, Offline: true})
+	if summary.CacheHits != 1 || summary.Images != 1 {
+		t.Errorf("offline run: hits=%d images=%d, want 1/1", summary.CacheHits, summary.Images)
+	}
+	embedded := false
+	for name, content := range files {
+		if strings.HasPrefix(name, "word/media/") && content == string(data) {
+			embedded = true
+		}
+	}
+	if !embedded {
+		t.Error("offline run did not embed the cached image bytes")
+	}
+	if got := atomic.LoadInt32(&downloads); got != 1 {
+		t.Errorf("full downloads = %d, want 1", got)
+	}
+}
+
+func TestOfflineExportWithoutCachedImage(t *testing.T) {
+	chapters := []ChapterData{{ID: "1", Chapter: "1", Body: `<p>text</p><p><img src="http://127.0.0.1:1/missing.png"></p>`}}
+
+	var buf bytes.Buffer
+	exporter := New(Options{CacheDir: t.TempDir(), Offline: true})
+	if err := exporter.Export(context.Background(), chapters, &buf); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	summary := exporter.Summary()
+	if summary.Images != 0 || summary.CacheMisses != 1 {
+		t.Errorf("images=%d misses=%d, want 0/1", summary.Images, summary.CacheMisses)
+	}
+}
+
+func TestImageCacheSkipsNonImageBody(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch r.URL.Path {
+		case "/error.png":
+			// หน้า error ที่ตอบ 200
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html>not found</html>"))
+		default:
+			http.Error(w, "gone", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	chapters := []ChapterData{{ID: "1", Chapter: "1", Body: `<p><img src="` + server.URL + `/error.png"></p><p><img src="` + server.URL + `/missing.png"></p>`}}
+	cacheDir := t.TempDir()
+
+	for run := 1; run <= 2; run++ {
+		var buf bytes.Buffer
+		exporter := New(Options{CacheDir: cacheDir})
+		if err := exporter.Export(context.Background(), chapters, &buf); err != nil {
+			t.Fatalf("Export: %v", err)
+		}
+		// ทั้งสอง URL ไปถึง network ทุกครั้ง จึงเป็น miss แม้จะล้มเหลว
+		if summary := exporter.Summary(); summary.CacheHits != 0 || summary.CacheMisses != 2 || summary.Images != 0 {
+			t.Errorf("run %d: hits=%d misses=%d images=%d, want 0/2/0", run, summary.CacheHits, summary.CacheMisses, summary.Images)
+		}
+	}
+	if _, _, ok := newImageCache(cacheDir).load(server.URL + "/error.png"); ok {
+		t.Error("HTML error page was stored in the image cache")
+	}
+	if got := atomic.LoadInt32(&requests); got != 4 {
+		t.Errorf("requests = %d, want 4", got)
+	}
+}
+
+func TestImageCacheRejectsMismatchedData(t *testing.T) {
+	cache := newImageCache(t.TempDir())
+	url := "https://example.com/cover.png"
+	if err := cache.store(cacheEntry{URL: url, ETag: `"v1"`}, []byte("version one")); err != nil {
+		t.Fatal(err)
+	}
+	if _, data, ok := cache.load(url); !ok || string(data) != "version one" {
+		t.Fatalf("load = %q, %v, want stored data", data, ok)
+	}
+
+	// .bin ถูกเขียนทับโดย export อื่นแต่ .json ยังเป็นของเดิม: ETag เดิมต้องไม่ใช้กับข้อมูลใหม่
+	dataPath, _ := cache.paths(url)
+	if err := os.WriteFile(dataPath, []byte("version two"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := cache.load(url); ok {
+		t.Error("load returned data that does not match its metadata")
+	}
+}
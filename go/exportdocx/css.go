@@ -0,0 +1,326 @@
+package exportdocx
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// cssProperty คืนค่าของ property ใน inline style (ตัวพิมพ์เล็ก ไม่รวม !important)
+// ถ้ามีหลาย declaration ใช้ตัวสุดท้ายเหมือน browser
+func cssProperty(style, property string) (string, bool) {
+	value, found := "", false
+	for _, decl := range strings.Split(style, ";") {
+		name, v, ok := strings.Cut(decl, ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(name), property) {
+			continue
+		}
+		v = strings.TrimSpace(strings.ToLower(v))
+		value, found = strings.TrimSpace(strings.TrimSuffix(v, "!important")), true
+	}
+	return value, found
+}
+
+// cssTokens แยกค่าของ shorthand ด้วยช่องว่าง โดยไม่แยกภายในวงเล็บ เช่น "1px solid rgb(0, 0, 0)"
+func cssTokens(value string) []string {
+	var tokens []string
+	depth, start := 0, -1
+	for i, r := range value {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case depth == 0 && (r == ' ' || r == '\t' || r == '\n'):
+			if start >= 0 {
+				tokens = append(tokens, value[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, value[start:])
+	}
+	return tokens
+}
+
+// cssLength อ่านความยาวของ property ใน inline style เป็น px
+// % คิดจาก relativeTo และ em คิดจาก fontSize (half-point) ค่าอย่าง auto หรือ fit-content คืน ok = false
+func cssLength(style, property string, relativeTo, fontSize int) (int, bool) {
+	value, ok := cssProperty(style, property)
+	if !ok {
+		return 0, false
+	}
+	if pct, ok := strings.CutSuffix(value, "%"); ok {
+		v, err := strconv.ParseFloat(strings.TrimSpace(pct), 64)
+		if err != nil || v < 0 {
+			return 0, false
+		}
+		return int(math.Round(float64(relativeTo) * v / 100)), true
+	}
+	twips, ok := cssTwips(value, fontSize)
+	if !ok || twips < 0 {
+		return 0, false
+	}
+	return int(math.Round(float64(twips) / twipsPerPx)), true
+}
+
+// รูปแบบเส้นของ text-decoration-style -> w:u
+var underlineStyles = map[string]string{
+	"solid":  "single",
+	"double": "double",
+	"dotted": "dotted",
+	"dashed": "dash",
+	"wavy":   "wave",
+}
+
+// textDecoration อ่าน text-decoration (หรือ text-decoration-line และ text-decoration-style)
+// คืนรูปแบบขีดเส้นใต้ ("" = ไม่มี) และขีดฆ่า (double = ขีดสองเส้น)
+// ok = false ถ้า style ไม่ได้กำหนด text-decoration
+func textDecoration(style string) (underline string, strike, double, ok bool) {
+	var tokens []string
+	for _, property := range []string{"text-decoration", "text-decoration-line", "text-decoration-style"} {
+		if value, found := cssProperty(style, property); found {
+			tokens = append(tokens, strings.Fields(value)...)
+			// text-decoration-style อย่างเดียวไม่ได้เปลี่ยนว่ามีเส้นหรือไม่
+			ok = ok || property != "text-decoration-style"
+		}
+	}
+
+	lineStyle := "solid"
+	for _, token := range tokens {
+		if _, known := underlineStyles[token]; known {
+			lineStyle = token
+		}
+	}
+	for _, token := range tokens {
+		switch token {
+		case "underline":
+			underline = underlineStyles[lineStyle]
+		case "line-through":
+			strike, double = true, lineStyle == "double"
+		}
+	}
+	return underline, strike, double, ok
+}
+
+// คำขนาดตัวอักษรของ CSS เทียบกับขนาดเริ่มต้นของเอกสาร (medium)
+var fontSizeKeywords = map[string]float64{
+	"xx-small":  3.0 / 5,
+	"x-small":   3.0 / 4,
+	"small":     8.0 / 9,
+	"medium":    1,
+	"large":     6.0 / 5,
+	"x-large":   3.0 / 2,
+	"xx-large":  2,
+	"xxx-large": 3,
+}
+
+// cssFontSize แปลง font-size เป็น half-point (w:sz)
+// em และ % คิดจาก parent (ขนาดที่สืบทอดมา) ส่วน rem และคำอย่าง large คิดจาก base (ขนาดเริ่มต้นของเอกสาร)
+func cssFontSize(value string, parent, base int) (int, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	size := 0.0
+	switch {
+	case fontSizeKeywords[value] > 0:
+		size = float64(base) * fontSizeKeywords[value]
+	case value == "smaller":
+		size = float64(parent) / 1.2
+	case value == "larger":
+		size = float64(parent) * 1.2
+	default:
+		number := strings.TrimRight(value, "abcdefghijklmnopqrstuvwxyz%")
+		n, err := strconv.ParseFloat(number, 64)
+		if err != nil || n <= 0 {
+			return 0, false
+		}
+		switch unit := value[len(number):]; unit {
+		case "pt":
+			size = n * 2
+		case "px":
+			size = n * 1.5 // 1px = 0.75pt
+		case "em":
+			size = n * float64(parent)
+		case "rem":
+			size = n * float64(base)
+		case "%":
+			size = n * float64(parent) / 100
+		case "mm", "cm", "in":
+			size = float64(lengthToTwips(number, unit)) / 10 // 1 half-point = 10 twips
+		default:
+			return 0, false
+		}
+	}
+	// Word รองรับ w:sz ตั้งแต่ 1 ถึง 3276 half-point
+	return min(max(int(math.Round(size)), 1), 3276), true
+}
+
+// cssFontWeight คืน true ถ้า font-weight เป็นตัวหนา (bold, bolder หรือ 600 ขึ้นไป)
+// ok = false ถ้าไม่ใช่ค่าที่รู้จัก
+func cssFontWeight(value string) (bold, ok bool) {
+	switch value {
+	case "bold", "bolder":
+		return true, true
+	case "normal", "lighter":
+		return false, true
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 1 && n <= 1000 {
+		return n >= 600, true
+	}
+	return false, false
+}
+
+// cssTwips แปลงความยาวของ CSS เป็น twips (ค่าติดลบได้ เช่น text-indent)
+// em และ rem คิดจาก fontSize (half-point) ส่วน % และ auto ไม่รองรับเพราะต้องรู้ความกว้างของ container
+func cssTwips(value string, fontSize int) (int, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	number := strings.TrimRight(value, "abcdefghijklmnopqrstuvwxyz%")
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, false
+	}
+	switch unit := value[len(number):]; unit {
+	case "px":
+		return int(math.Round(n * 15)), true // 1px = 0.75pt = 15 twips
+	case "pt", "mm", "cm", "in":
+		return lengthToTwips(number, unit), true
+	case "em", "rem":
+		return int(math.Round(n * float64(fontSize) * 10)), true
+	case "":
+		// ตัวเลขไม่มีหน่วยใช้ได้แค่ 0
+		return 0, n == 0
+	}
+	return 0, false
+}
+
+// cssBoxSide คืนค่าด้านหนึ่ง (top, right, bottom, left) ของ margin หรือ padding
+// อ่านทั้ง property แบบแยกด้าน เช่น margin-left และแบบย่อ 1-4 ค่า โดยแบบแยกด้านมีผลกว่า
+func cssBoxSide(style, property, side string) (string, bool) {
+	if value, ok := cssProperty(style, property+"-"+side); ok {
+		return value, true
+	}
+	value, ok := cssProperty(style, property)
+	if !ok {
+		return "", false
+	}
+	values := strings.Fields(value)
+	index := map[string][4]int{
+		"top":    {0, 0, 0, 0},
+		"right":  {0, 1, 1, 1},
+		"bottom": {0, 0, 2, 2},
+		"left":   {0, 1, 1, 3},
+	}[side]
+	if len(values) == 0 || len(values) > 4 {
+		return "", false
+	}
+	return values[index[len(values)-1]], true
+}
+
+// ค่า text-align -> w:jc
+var textAlignments = map[string]string{
+	"left":        "left",
+	"start":       "left",
+	"right":       "right",
+	"end":         "right",
+	"center":      "center",
+	"justify":     "both",
+	"justify-all": "distribute", // ยืดบรรทัดสุดท้ายด้วย
+}
+
+// applyParagraphCSS ใส่ w:jc, w:ind และ w:spacing จาก text-align, margin, padding,
+// text-indent และ line-height ของ block element (fontSize ใช้กับหน่วย em)
+func applyParagraphCSS(props *PPr, style string, fontSize int) {
+	if value, ok := cssProperty(style, "text-align"); ok && textAlignments[value] != "" {
+		jc := textAlignments[value]
+		if justify, _ := cssProperty(style, "text-justify"); jc == "both" && (justify == "inter-character" || justify == "distribute") {
+			jc = "distribute"
+		}
+		props.Jc = &Jc{Val: jc}
+	}
+
+	// ระยะเยื้องซ้ายขวา = margin + padding
+	side := func(name string) (int, bool) {
+		total, found := 0, false
+		for _, property := range []string{"margin", "padding"} {
+			if value, ok := cssBoxSide(style, property, name); ok {
+				if twips, ok := cssTwips(value, fontSize); ok {
+					total, found = total+twips, true
+				}
+			}
+		}
+		return total, found
+	}
+	// rule และ style attribute เรียกฟังก์ชันนี้ต่อกัน จึงเขียนทับเฉพาะค่าที่มีใน style นี้
+	ind := Ind{}
+	if props.Ind != nil {
+		ind = *props.Ind
+	}
+	if left, ok := side("left"); ok {
+		ind.Left = strconv.Itoa(left)
+	}
+	if right, ok := side("right"); ok {
+		ind.Right = strconv.Itoa(right)
+	}
+	if value, ok := cssProperty(style, "text-indent"); ok {
+		if indent, ok := cssTwips(value, fontSize); ok {
+			// text-indent ติดลบคือ hanging indent
+			ind.FirstLine, ind.Hanging = "", ""
+			if indent < 0 {
+				ind.Hanging = strconv.Itoa(-indent)
+			} else {
+				ind.FirstLine = strconv.Itoa(indent)
+			}
+		}
+	}
+	if ind != (Ind{}) {
+		props.Ind = &ind
+	}
+
+	// ระยะก่อนและหลังย่อหน้าจาก margin (Word ไม่รองรับค่าติดลบ)
+	spacing := Spacing{}
+	if props.Spacing != nil {
+		spacing = *props.Spacing
+	}
+	if value, ok := cssBoxSide(style, "margin", "top"); ok {
+		if twips, ok := cssTwips(value, fontSize); ok {
+			spacing.Before = strconv.Itoa(max(twips, 0))
+		}
+	}
+	if value, ok := cssBoxSide(style, "margin", "bottom"); ok {
+		if twips, ok := cssTwips(value, fontSize); ok {
+			spacing.After = strconv.Itoa(max(twips, 0))
+		}
+	}
+	if value, ok := cssProperty(style, "line-height"); ok {
+		if line, rule := lineHeight(value, fontSize); line != "" {
+			spacing.Line, spacing.LineRule = line, rule
+		}
+	}
+	if spacing != (Spacing{}) {
+		props.Spacing = &spacing
+	}
+}
+
+// lineHeight แปลง line-height เป็น w:line และ w:lineRule
+// ตัวเลข, % และ em เป็นสัดส่วนของบรรทัด (auto) ส่วนความยาวอื่นเป็นระยะคงที่ (exact)
+func lineHeight(value string, fontSize int) (line, rule string) {
+	number := strings.TrimRight(value, "abcdefghijklmnopqrstuvwxyz%")
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || n <= 0 {
+		return "", ""
+	}
+	switch unit := value[len(number):]; unit {
+	case "", "em":
+		return strconv.Itoa(int(math.Round(n * 240))), "auto"
+	case "%":
+		return strconv.Itoa(int(math.Round(n * 240 / 100))), "auto"
+	}
+	if twips, ok := cssTwips(value, fontSize); ok {
+		return strconv.Itoa(twips), "exact"
+	}
+	return "", ""
+}
@@ -0,0 +1,155 @@
+package exportdocx
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestInlineTextEffects(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string // w:rPr ของ run แรก
+	}{
+		{"u", "<u>x</u>", `<w:rPr><w:u w:val="single"></w:u></w:rPr>`},
+		{"ins", "<ins>x</ins>", `<w:rPr><w:u w:val="single"></w:u></w:rPr>`},
+		{"del", "<del>x</del>", `<w:rPr><w:strike></w:strike></w:rPr>`},
+		{"s", "<s>x</s>", `<w:rPr><w:strike></w:strike></w:rPr>`},
+		{"sup", "<sup>x</sup>", `<w:rPr><w:vertAlign w:val="superscript"></w:vertAlign></w:rPr>`},
+		{"sub", "<sub>x</sub>", `<w:rPr><w:vertAlign w:val="subscript"></w:vertAlign></w:rPr>`},
+		{"mark", "<mark>x</mark>", `<w:rPr><w:highlight w:val="yellow"></w:highlight></w:rPr>`},
+		{"mark with background", `<mark style="background-color: #fc0">x</mark>`, `<w:rPr><w:shd w:val="clear" w:color="auto" w:fill="FFCC00"></w:shd></w:rPr>`},
+		{"css underline wavy", `<span style="text-decoration: underline wavy">x</span>`, `<w:rPr><w:u w:val="wave"></w:u></w:rPr>`},
+		{"css double line-through", `<span style="text-decoration-line: line-through; text-decoration-style: double">x</span>`, `<w:rPr><w:dstrike></w:dstrike></w:rPr>`},
+		{"css vertical-align", `<span style="vertical-align: super">x</span>`, `<w:rPr><w:vertAlign w:val="superscript"></w:vertAlign></w:rPr>`},
+		{"css small caps", `<span style="font-variant: small-caps">x</span>`, `<w:rPr><w:smallCaps></w:smallCaps></w:rPr>`},
+		{"css uppercase", `<span style="text-transform: uppercase">x</span>`, `<w:rPr><w:caps></w:caps></w:rPr>`},
+		{"css none cancels tag", `<u><span style="text-decoration: none">x</span></u>`, ``},
+		{"decoration style keeps line", `<u><span style="text-decoration-style: dotted">x</span></u>`, `<w:rPr><w:u w:val="single"></w:u></w:rPr>`},
+		{"link without underline", `<a href="https://example.com" style="text-decoration: none">x</a>`, `<w:rPr><w:rStyle w:val="Hyperlink"></w:rStyle><w:u w:val="none"></w:u></w:rPr>`},
+		{"schema order", `<sup><u><s><mark style="color: #ff0000">x</mark></s></u></sup>`, `<w:rPr><w:strike></w:strike><w:color w:val="FF0000"></w:color><w:highlight w:val="yellow"></w:highlight><w:u w:val="single"></w:u><w:vertAlign w:val="superscript"></w:vertAlign></w:rPr>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := New(Options{}).parseContentToRuns(parseFragment(t, tt.html), runStyle{})
+			if len(runs) == 0 {
+				t.Fatalf("no runs for %q", tt.html)
+			}
+			got := ""
+			if runs[0].Props != nil {
+				data, err := xml.Marshal(runs[0].Props)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = string(data)
+			}
+			if got != tt.want {
+				t.Errorf("rPr of %q\n got %s\nwant %s", tt.html, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSSProperty(t *testing.T) {
+	style := "color: red; Text-Decoration: Underline !important; color: #00F"
+	if got, ok := cssProperty(style, "text-decoration"); !ok || got != "underline" {
+		t.Errorf("text-decoration = %q, %v", got, ok)
+	}
+	if got, _ := cssProperty(style, "color"); got != "#00f" {
+		t.Errorf("last color declaration should win, got %q", got)
+	}
+	if _, ok := cssProperty(style, "font-size"); ok {
+		t.Errorf("font-size should not be found")
+	}
+	if got, ok := cssColor("#0af"); !ok || got != "00AAFF" {
+		t.Errorf("cssColor(#0af) = %q, %v", got, ok)
+	}
+}
+
+func TestInlineFont(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string // w:rPr ของ run แรก
+	}{
+		{"named color", `<span style="color: darkslategray">x</span>`, `<w:rPr><w:color w:val="2F4F4F"></w:color></w:rPr>`},
+		{"rgb color", `<span style="color: rgb(255 0 0 / 50%)">x</span>`, `<w:rPr><w:color w:val="FF8080"></w:color></w:rPr>`},
+		{"px", `<span style="font-size: 20px">x</span>`, `<w:rPr><w:sz w:val="30"></w:sz><w:szCs w:val="30"></w:szCs></w:rPr>`},
+		{"em of parent", `<span style="font-size: 10pt"><span style="font-size: 1.5em">x</span></span>`, `<w:rPr><w:sz w:val="30"></w:sz><w:szCs w:val="30"></w:szCs></w:rPr>`},
+		{"percent of base", `<span style="font-size: 50%">x</span>`, `<w:rPr><w:sz w:val="16"></w:sz><w:szCs w:val="16"></w:szCs></w:rPr>`},
+		{"rem keeps base", `<span style="font-size: 8pt"><span style="font-size: 2rem">x</span></span>`, `<w:rPr><w:sz w:val="64"></w:sz><w:szCs w:val="64"></w:szCs></w:rPr>`},
+		{"keyword", `<span style="font-size: x-large">x</span>`, `<w:rPr><w:sz w:val="48"></w:sz><w:szCs w:val="48"></w:szCs></w:rPr>`},
+		{"bold", `<span style="font-weight: 700">x</span>`, `<w:rPr><w:b></w:b><w:bCs></w:bCs></w:rPr>`},
+		{"light", `<span style="font-weight: 300">x</span>`, `<w:rPr><w:b w:val="0"></w:b><w:bCs w:val="0"></w:bCs></w:rPr>`},
+		{"normal cancels tag", `<b><span style="font-weight: normal">x</span></b>`, `<w:rPr><w:b w:val="0"></w:b><w:bCs w:val="0"></w:bCs></w:rPr>`},
+		{"italic", `<span style="font-style: italic">x</span>`, `<w:rPr><w:i></w:i><w:iCs></w:iCs></w:rPr>`},
+		{"tag after css", `<span style="font-style: normal"><em>x</em></span>`, `<w:rPr><w:rStyle w:val="Emphasis"></w:rStyle></w:rPr>`},
+		{"unknown values ignored", `<span style="color: nope; font-size: big; font-weight: heavy">x</span>`, ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := New(Options{}).parseContentToRuns(parseFragment(t, tt.html), runStyle{BaseSize: 32})
+			if len(runs) == 0 {
+				t.Fatalf("no runs for %q", tt.html)
+			}
+			got := ""
+			if runs[0].Props != nil {
+				data, err := xml.Marshal(runs[0].Props)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = string(data)
+			}
+			if got != tt.want {
+				t.Errorf("rPr of %q\n got %s\nwant %s", tt.html, got, tt.want)
+			}
+		})
+	}
+
+	if size, ok := cssFontSize("smaller", 24, 32); !ok || size != 20 {
+		t.Errorf("cssFontSize(smaller) = %d, %v", size, ok)
+	}
+}
+
+func TestParagraphCSS(t *testing.T) {
+	tests := []struct {
+		style string
+		want  string // w:pPr ที่ไม่มี pStyle
+	}{
+		{"text-align: right", `<w:pPr><w:jc w:val="right"></w:jc></w:pPr>`},
+		{"text-align: justify", `<w:pPr><w:jc w:val="both"></w:jc></w:pPr>`},
+		{"text-align: justify; text-justify: inter-character", `<w:pPr><w:jc w:val="distribute"></w:jc></w:pPr>`},
+		{"text-align: justify-all", `<w:pPr><w:jc w:val="distribute"></w:jc></w:pPr>`},
+		{"margin-left: 1in; padding-left: 10px; margin-right: 2em", `<w:pPr><w:ind w:left="1590" w:right="320"></w:ind></w:pPr>`},
+		{"margin: 12pt 0.5in", `<w:pPr><w:spacing w:before="240" w:after="240"></w:spacing><w:ind w:left="720" w:right="720"></w:ind></w:pPr>`},
+		{"margin: 0 0 6pt; text-indent: 2em", `<w:pPr><w:spacing w:before="0" w:after="120"></w:spacing><w:ind w:left="0" w:right="0" w:firstLine="320"></w:ind></w:pPr>`},
+		{"padding-left: 24pt; text-indent: -24pt", `<w:pPr><w:ind w:left="480" w:hanging="480"></w:ind></w:pPr>`},
+		{"line-height: 1.5", `<w:pPr><w:spacing w:line="360" w:lineRule="auto"></w:spacing></w:pPr>`},
+		{"line-height: 200%", `<w:pPr><w:spacing w:line="480" w:lineRule="auto"></w:spacing></w:pPr>`},
+		{"line-height: 18pt; margin-top: -5px", `<w:pPr><w:spacing w:before="0" w:line="360" w:lineRule="exact"></w:spacing></w:pPr>`},
+		{"margin-left: auto; line-height: normal; text-align: inherit", `<w:pPr></w:pPr>`},
+	}
+	for _, tt := range tests {
+		props := &PPr{}
+		applyParagraphCSS(props, tt.style, 16)
+		data, err := xml.Marshal(props)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(data); got != tt.want {
+			t.Errorf("applyParagraphCSS(%q)\n got %s\nwant %s", tt.style, got, tt.want)
+		}
+	}
+	// rule แล้วตามด้วย style attribute: ค่าที่ style ไม่ได้กำหนดยังคงมาจาก rule
+	props := &PPr{}
+	applyParagraphCSS(props, "margin-left: 1in; margin-top: 6pt; margin-bottom: 6pt; text-indent: -12pt", 16)
+	applyParagraphCSS(props, "text-indent: 2em; line-height: 1.5", 16)
+	data, err := xml.Marshal(props)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<w:pPr><w:spacing w:before="120" w:after="120" w:line="360" w:lineRule="auto"></w:spacing><w:ind w:left="1440" w:firstLine="320"></w:ind></w:pPr>`
+	if got := string(data); got != want {
+		t.Errorf("merged paragraph CSS\n got %s\nwant %s", got, want)
+	}
+}
@@ -0,0 +1,338 @@
+package exportdocx
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// cssColor แปลงสีของ CSS Color Level 4 เป็น hex ตัวพิมพ์ใหญ่ 6 หลักสำหรับ w:color และ w:fill
+// รองรับ #rgb, #rgba, #rrggbb, #rrggbbaa, ชื่อสี, rgb(), rgba(), hsl(), hsla(), hwb(),
+// lab(), lch(), oklab(), oklch() และ color(srgb ...) / color(srgb-linear ...)
+// Word ไม่มีความโปร่งใส สีที่มี alpha จึงผสมกับพื้นขาว (alpha 0 และ transparent คืน ok = false)
+func cssColor(value string) (string, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	var r, g, b, alpha float64
+	alpha = 1
+
+	switch open := strings.IndexByte(value, '('); {
+	case strings.HasPrefix(value, "#"):
+		var ok bool
+		if r, g, b, alpha, ok = parseHexColor(value[1:]); !ok {
+			return "", false
+		}
+	case open > 0 && strings.HasSuffix(value, ")"):
+		args, a, ok := colorFunctionArgs(value[open+1 : len(value)-1])
+		if !ok {
+			return "", false
+		}
+		if r, g, b, ok = colorFunction(value[:open], args); !ok {
+			return "", false
+		}
+		alpha = a
+	default:
+		hex, ok := namedColors[value]
+		if !ok {
+			return "", false
+		}
+		r, g, b, _, _ = parseHexColor(hex)
+	}
+
+	if alpha <= 0 {
+		return "", false
+	}
+	channel := func(c float64) int {
+		c = c*alpha + 1*(1-alpha)
+		return int(math.Round(math.Min(math.Max(c, 0), 1) * 255))
+	}
+	return fmt.Sprintf("%02X%02X%02X", channel(r), channel(g), channel(b)), true
+}
+
+// parseHexColor อ่าน hex 3, 4, 6 หรือ 8 หลัก เป็นค่า 0-1
+func parseHexColor(hex string) (r, g, b, alpha float64, ok bool) {
+	if len(hex) == 3 || len(hex) == 4 {
+		expanded := make([]byte, 0, 8)
+		for i := range len(hex) {
+			expanded = append(expanded, hex[i], hex[i])
+		}
+		hex = string(expanded)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return 0, 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, 0, 0, 0, false
+	}
+	return float64(v>>24&0xff) / 255, float64(v>>16&0xff) / 255, float64(v>>8&0xff) / 255, float64(v&0xff) / 255, true
+}
+
+// colorArg คือ argument หนึ่งตัวของฟังก์ชันสี
+type colorArg struct {
+	Value   float64
+	Unit    string // "", "%" หรือหน่วยมุม (deg, rad, grad, turn)
+	Keyword string // เช่น srgb ใน color()
+}
+
+// colorFunctionArgs แยก argument ทั้งแบบเดิม (คั่นด้วย , และ alpha ตัวที่สี่)
+// และแบบ Level 4 (คั่นด้วยช่องว่าง และ alpha หลัง /)
+func colorFunctionArgs(s string) (args []colorArg, alpha float64, ok bool) {
+	alpha = 1
+	var alphaPart string
+	if before, after, found := strings.Cut(s, "/"); found {
+		s, alphaPart = before, after
+	}
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	if alphaPart == "" && strings.Contains(s, ",") && len(fields) == 4 {
+		fields, alphaPart = fields[:3], fields[3]
+	}
+
+	for _, field := range fields {
+		arg, ok := parseColorArg(field)
+		if !ok {
+			return nil, 0, false
+		}
+		args = append(args, arg)
+	}
+	if alphaPart != "" {
+		arg, ok := parseColorArg(strings.TrimSpace(alphaPart))
+		if !ok || arg.Keyword != "" {
+			return nil, 0, false
+		}
+		alpha = arg.Value
+		if arg.Unit == "%" {
+			alpha /= 100
+		}
+	}
+	return args, math.Min(math.Max(alpha, 0), 1), true
+}
+
+func parseColorArg(field string) (colorArg, bool) {
+	if field == "none" {
+		return colorArg{}, true
+	}
+	number := strings.TrimRight(field, "abcdefghijklmnopqrstuvwxyz%-")
+	if number == "" {
+		return colorArg{Keyword: field}, true
+	}
+	v, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return colorArg{}, false
+	}
+	unit := field[len(number):]
+	switch unit {
+	case "", "%", "deg", "rad", "grad", "turn":
+		return colorArg{Value: v, Unit: unit}, true
+	}
+	return colorArg{}, false
+}
+
+// number คืนค่าของ argument โดย 100% = percentScale
+func (a colorArg) number(percentScale float64) float64 {
+	if a.Unit == "%" {
+		return a.Value / 100 * percentScale
+	}
+	return a.Value
+}
+
+// hue คืนมุมเป็นองศา
+func (a colorArg) hue() float64 {
+	switch a.Unit {
+	case "rad":
+		return a.Value * 180 / math.Pi
+	case "grad":
+		return a.Value * 0.9
+	case "turn":
+		return a.Value * 360
+	}
+	return a.Value
+}
+
+// colorFunction แปลงฟังก์ชันสีเป็น sRGB (ค่า 0-1 ก่อน clamp)
+func colorFunction(name string, args []colorArg) (r, g, b float64, ok bool) {
+	switch name {
+	case "rgb", "rgba":
+		if len(args) != 3 {
+			return 0, 0, 0, false
+		}
+		return args[0].number(255) / 255, args[1].number(255) / 255, args[2].number(255) / 255, true
+	case "hsl", "hsla":
+		if len(args) != 3 {
+			return 0, 0, 0, false
+		}
+		r, g, b = hslToRGB(args[0].hue(), args[1].number(100)/100, args[2].number(100)/100)
+		return r, g, b, true
+	case "hwb":
+		if len(args) != 3 {
+			return 0, 0, 0, false
+		}
+		white, black := args[1].number(100)/100, args[2].number(100)/100
+		if white+black >= 1 {
+			gray := white / (white + black)
+			return gray, gray, gray, true
+		}
+		r, g, b = hslToRGB(args[0].hue(), 1, 0.5)
+		scale := func(c float64) float64 { return c*(1-white-black) + white }
+		return scale(r), scale(g), scale(b), true
+	case "lab":
+		if len(args) != 3 {
+			return 0, 0, 0, false
+		}
+		r, g, b = labToSRGB(args[0].number(100), args[1].number(125), args[2].number(125))
+		return r, g, b, true
+	case "lch":
+		if len(args) != 3 {
+			return 0, 0, 0, false
+		}
+		c, h := args[1].number(150), args[2].hue()*math.Pi/180
+		r, g, b = labToSRGB(args[0].number(100), c*math.Cos(h), c*math.Sin(h))
+		return r, g, b, true
+	case "oklab":
+		if len(args) != 3 {
+			return 0, 0, 0, false
+		}
+		r, g, b = oklabToSRGB(args[0].number(1), args[1].number(0.4), args[2].number(0.4))
+		return r, g, b, true
+	case "oklch":
+		if len(args) != 3 {
+			return 0, 0, 0, false
+		}
+		c, h := args[1].number(0.4), args[2].hue()*math.Pi/180
+		r, g, b = oklabToSRGB(args[0].number(1), c*math.Cos(h), c*math.Sin(h))
+		return r, g, b, true
+	case "color":
+		if len(args) != 4 {
+			return 0, 0, 0, false
+		}
+		r, g, b = args[1].number(1), args[2].number(1), args[3].number(1)
+		switch args[0].Keyword {
+		case "srgb":
+			return r, g, b, true
+		case "srgb-linear":
+			return gammaEncode(r), gammaEncode(g), gammaEncode(b), true
+		}
+	}
+	return 0, 0, 0, false
+}
+
+func hslToRGB(h, s, l float64) (r, g, b float64) {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	s, l = math.Min(math.Max(s, 0), 1), math.Min(math.Max(l, 0), 1)
+	f := func(n float64) float64 {
+		k := math.Mod(n+h/30, 12)
+		a := s * math.Min(l, 1-l)
+		return l - a*math.Max(-1, math.Min(math.Min(k-3, 9-k), 1))
+	}
+	return f(0), f(8), f(4)
+}
+
+// gammaEncode แปลง linear sRGB เป็น sRGB
+func gammaEncode(c float64) float64 {
+	sign := 1.0
+	if c < 0 {
+		sign, c = -1, -c
+	}
+	if c <= 0.0031308 {
+		return sign * 12.92 * c
+	}
+	return sign * (1.055*math.Pow(c, 1/2.4) - 0.055)
+}
+
+// linearXYZToSRGB แปลง XYZ (D65) เป็น sRGB
+func linearXYZToSRGB(x, y, z float64) (r, g, b float64) {
+	r = 3.2409699419045226*x - 1.537383177570094*y - 0.4986107602930034*z
+	g = -0.9692436362808796*x + 1.8759675015077202*y + 0.04155505740717559*z
+	b = 0.05563007969699366*x - 0.20397695888897652*y + 1.0569715142428786*z
+	return gammaEncode(r), gammaEncode(g), gammaEncode(b)
+}
+
+// labToSRGB แปลง CIE Lab (D50) เป็น sRGB ตามสูตรใน CSS Color Level 4
+func labToSRGB(l, a, bb float64) (r, g, b float64) {
+	const (
+		kappa   = 24389.0 / 27
+		epsilon = 216.0 / 24389
+	)
+	fy := (l + 16) / 116
+	fx := fy + a/500
+	fz := fy - bb/200
+	finv := func(t float64) float64 {
+		if t*t*t > epsilon {
+			return t * t * t
+		}
+		return (116*t - 16) / kappa
+	}
+	y := l / kappa
+	if l > kappa*epsilon {
+		y = fy * fy * fy
+	}
+	// จุดขาว D50
+	x := finv(fx) * 0.3457 / 0.3585
+	z := finv(fz) * (1 - 0.3457 - 0.3585) / 0.3585
+
+	// Bradford D50 -> D65
+	x65 := 0.955473421488075*x - 0.02309845494876471*y + 0.06325924320057072*z
+	y65 := -0.0283697093338637*x + 1.0099953980813041*y + 0.021041441191917323*z
+	z65 := 0.012314014864481998*x - 0.020507649298898964*y + 1.330365926242124*z
+	return linearXYZToSRGB(x65, y65, z65)
+}
+
+// oklabToSRGB แปลง OKLab เป็น sRGB
+func oklabToSRGB(l, a, bb float64) (r, g, b float64) {
+	lp := l + 0.3963377774*a + 0.2158037573*bb
+	mp := l - 0.1055613458*a - 0.0638541728*bb
+	sp := l - 0.0894841775*a - 1.2914855480*bb
+	lc, mc, sc := lp*lp*lp, mp*mp*mp, sp*sp*sp
+
+	r = 4.0767416621*lc - 3.3077115913*mc + 0.2309699292*sc
+	g = -1.2684380046*lc + 2.6097574011*mc - 0.3413193965*sc
+	b = -0.0041960863*lc - 0.7034186147*mc + 1.7076147010*sc
+	return gammaEncode(r), gammaEncode(g), gammaEncode(b)
+}
+
+// namedColors คือชื่อสีทั้งหมดของ CSS
+var namedColors = map[string]string{
+	"aliceblue": "f0f8ff", "antiquewhite": "faebd7", "aqua": "00ffff", "aquamarine": "7fffd4",
+	"azure": "f0ffff", "beige": "f5f5dc", "bisque": "ffe4c4", "black": "000000",
+	"blanchedalmond": "ffebcd", "blue": "0000ff", "blueviolet": "8a2be2", "brown": "a52a2a",
+	"burlywood": "deb887", "cadetblue": "5f9ea0", "chartreuse": "7fff00", "chocolate": "d2691e",
+	"coral": "ff7f50", "cornflowerblue": "6495ed", "cornsilk": "fff8dc", "crimson": "dc143c",
+	"cyan": "00ffff", "darkblue": "00008b", "darkcyan": "008b8b", "darkgoldenrod": "b8860b",
+	"darkgray": "a9a9a9", "darkgreen": "006400", "darkgrey": "a9a9a9", "darkkhaki": "bdb76b",
+	"darkmagenta": "8b008b", "darkolivegreen": "556b2f", "darkorange": "ff8c00", "darkorchid": "9932cc",
+	"darkred": "8b0000", "darksalmon": "e9967a", "darkseagreen": "8fbc8f", "darkslateblue": "483d8b",
+	"darkslategray": "2f4f4f", "darkslategrey": "2f4f4f", "darkturquoise": "00ced1", "darkviolet": "9400d3",
+	"deeppink": "ff1493", "deepskyblue": "00bfff", "dimgray": "696969", "dimgrey": "696969",
+	"dodgerblue": "1e90ff", "firebrick": "b22222", "floralwhite": "fffaf0", "forestgreen": "228b22",
+	"fuchsia": "ff00ff", "gainsboro": "dcdcdc", "ghostwhite": "f8f8ff", "gold": "ffd700",
+	"goldenrod": "daa520", "gray": "808080", "green": "008000", "greenyellow": "adff2f",
+	"grey": "808080", "honeydew": "f0fff0", "hotpink": "ff69b4", "indianred": "cd5c5c",
+	"indigo": "4b0082", "ivory": "fffff0", "khaki": "f0e68c", "lavender": "e6e6fa",
+	"lavenderblush": "fff0f5", "lawngreen": "7cfc00", "lemonchiffon": "fffacd", "lightblue": "add8e6",
+	"lightcoral": "f08080", "lightcyan": "e0ffff", "lightgoldenrodyellow": "fafad2", "lightgray": "d3d3d3",
+	"lightgreen": "90ee90", "lightgrey": "d3d3d3", "lightpink": "ffb6c1", "lightsalmon": "ffa07a",
+	"lightseagreen": "20b2aa", "lightskyblue": "87cefa", "lightslategray": "778899", "lightslategrey": "778899",
+	"lightsteelblue": "b0c4de", "lightyellow": "ffffe0", "lime": "00ff00", "limegreen": "32cd32",
+	"linen": "faf0e6", "magenta": "ff00ff", "maroon": "800000", "mediumaquamarine": "66cdaa",
+	"mediumblue": "0000cd", "mediumorchid": "ba55d3", "mediumpurple": "9370db", "mediumseagreen": "3cb371",
+	"mediumslateblue": "7b68ee", "mediumspringgreen": "00fa9a", "mediumturquoise": "48d1cc", "mediumvioletred": "c71585",
+	"midnightblue": "191970", "mintcream": "f5fffa", "mistyrose": "ffe4e1", "moccasin": "ffe4b5",
+	"navajowhite": "ffdead", "navy": "000080", "oldlace": "fdf5e6", "olive": "808000",
+	"olivedrab": "6b8e23", "orange": "ffa500", "orangered": "ff4500", "orchid": "da70d6",
+	"palegoldenrod": "eee8aa", "palegreen": "98fb98", "paleturquoise": "afeeee", "palevioletred": "db7093",
+	"papayawhip": "ffefd5", "peachpuff": "ffdab9", "peru": "cd853f", "pink": "ffc0cb",
+	"plum": "dda0dd", "powderblue": "b0e0e6", "purple": "800080", "rebeccapurple": "663399",
+	"red": "ff0000", "rosybrown": "bc8f8f", "royalblue": "4169e1", "saddlebrown": "8b4513",
+	"salmon": "fa8072", "sandybrown": "f4a460", "seagreen": "2e8b57", "seashell": "fff5ee",
+	"sienna": "a0522d", "silver": "c0c0c0", "skyblue": "87ceeb", "slateblue": "6a5acd",
+	"slategray": "708090", "slategrey": "708090", "snow": "fffafa", "springgreen": "00ff7f",
+	"steelblue": "4682b4", "tan": "d2b48c", "teal": "008080", "thistle": "d8bfd8",
+	"tomato": "ff6347", "turquoise": "40e0d0", "violet": "ee82ee", "wheat": "f5deb3",
+	"white": "ffffff", "whitesmoke": "f5f5f5", "yellow": "ffff00", "yellowgreen": "9acd32",
+}
@@ -0,0 +1,41 @@
+package exportdocx
+
+import "testing"
+
+func TestCSSColor(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{"#f00", "FF0000", true},
+		{"#ff000080", "FF7F7F", true},
+		{"#F0F8FF", "F0F8FF", true},
+		{"red", "FF0000", true},
+		{"DarkSlateGray", "2F4F4F", true},
+		{"rgb(255, 128, 0)", "FF8000", true},
+		{"rgb(100% 50% 0%)", "FF8000", true},
+		{"rgba(0, 0, 255, 0.5)", "8080FF", true},
+		{"rgb(0 0 255 / 50%)", "8080FF", true},
+		{"hsl(120, 100%, 25%)", "008000", true},
+		{"hsl(0.5turn 100% 50%)", "00FFFF", true},
+		{"hwb(240 0% 0%)", "0000FF", true},
+		{"lab(54.29 80.82 69.89)", "FF0000", true},
+		{"lch(54.29 106.84 40.85)", "FF0000", true},
+		{"oklab(0.628 0.2249 0.1258)", "FF0000", true},
+		{"oklch(62.8% 0.2577 29.23deg)", "FF0000", true},
+		{"color(srgb 0 0.5 1)", "0080FF", true},
+		{"color(srgb-linear 1 1 1)", "FFFFFF", true},
+		{"transparent", "", false},
+		{"rgba(0, 0, 0, 0)", "", false},
+		{"#12345", "", false},
+		{"notacolor", "", false},
+		{"rgb(1, 2)", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := cssColor(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("cssColor(%q) = %q, %v; want %q, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
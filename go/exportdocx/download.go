@@ -0,0 +1,313 @@
+package exportdocx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ค่าเริ่มต้นของการดาวน์โหลดรูปภาพ
+const (
+	defaultDownloadWorkers = 8
+	defaultDownloadTimeout = 30 * time.Second
+	defaultRetryBackoff    = 500 * time.Millisecond
+	defaultMaxImageSize    = 20 << 20
+)
+
+// fetchedImage คือผลการดาวน์โหลดหนึ่ง URL
+type fetchedImage struct {
+	Data         []byte
+	ContentType  string
+	ETag         string
+	LastModified string
+	NotModified  bool // server ตอบ 304 (ใช้ข้อมูลจาก cache)
+	Err          error
+}
+
+// imageFetcher ดาวน์โหลดรูปภาพด้วย worker pool และเก็บผลไว้ตลอดการ export หนึ่งครั้ง
+// URL เดียวกันจะถูกดาวน์โหลดเพียงครั้งเดียว
+type imageFetcher struct {
+	client  *http.Client
+	workers int
+	timeout time.Duration
+	retries int
+	backoff time.Duration
+	maxSize int64
+	logf    func(format string, args ...interface{})
+
+	// โฟลเดอร์ที่ใช้หา path แบบ relative ของรูปในเครื่อง
+	assetsDir string
+
+	// cache บนดิสก์ (nil = ไม่ใช้) และโหมด offline ที่ไม่เรียก network เลย
+	disk    *imageCache
+	offline bool
+	hits    int64
+	misses  int64
+
+	mu    sync.Mutex
+	cache map[string]*pendingFetch
+}
+
+// pendingFetch คือการโหลด URL หนึ่ง ผู้เรียก URL เดียวกันระหว่างโหลดจะรอผลเดียวกันจาก done
+type pendingFetch struct {
+	done   chan struct{}
+	result *fetchedImage
+}
+
+func newImageFetcher(opts Options, logf func(format string, args ...interface{})) *imageFetcher {
+	f := &imageFetcher{
+		client:    opts.HTTPClient,
+		workers:   opts.DownloadWorkers,
+		timeout:   opts.DownloadTimeout,
+		retries:   opts.DownloadRetries,
+		backoff:   opts.RetryBackoff,
+		maxSize:   opts.MaxImageSize,
+		logf:      logf,
+		offline:   opts.Offline,
+		cache:     make(map[string]*pendingFetch),
+		assetsDir: opts.AssetsDir,
+	}
+	if opts.CacheDir != "" {
+		f.disk = newImageCache(opts.CacheDir)
+	}
+	if f.client == nil {
+		f.client = http.DefaultClient
+	}
+	if f.workers <= 0 {
+		f.workers = defaultDownloadWorkers
+	}
+	if f.timeout <= 0 {
+		f.timeout = defaultDownloadTimeout
+	}
+	if f.retries < 0 {
+		f.retries = 0
+	}
+	if f.backoff <= 0 {
+		f.backoff = defaultRetryBackoff
+	}
+	if f.maxSize <= 0 {
+		f.maxSize = defaultMaxImageSize
+	}
+	return f
+}
+
+// prefetch ดาวน์โหลดทุก URL พร้อมกัน (ข้าม URL ที่ซ้ำหรือโหลดแล้ว)
+func (f *imageFetcher) prefetch(ctx context.Context, urls []string) {
+	var pending []string
+	seen := make(map[string]bool)
+	f.mu.Lock()
+	for _, url := range urls {
+		if _, ok := f.cache[url]; ok || seen[url] {
+			continue
+		}
+		seen[url] = true
+		pending = append(pending, url)
+	}
+	f.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+	f.logf("⬇️ Downloading %d images with %d workers\n", len(pending), f.workers)
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < f.workers && i < len(pending); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for url := range jobs {
+				f.fetch(ctx, url)
+			}
+		}()
+	}
+
+loop:
+	for _, url := range pending {
+		select {
+		case jobs <- url:
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// fetch คืนผลจาก cache หรือดาวน์โหลดใหม่ถ้ายังไม่เคยโหลด
+// ถ้า URL กำลังโหลดอยู่ใน goroutine อื่นจะรอผลนั้นแทนการโหลดซ้ำ
+func (f *imageFetcher) fetch(ctx context.Context, url string) *fetchedImage {
+	f.mu.Lock()
+	pending, ok := f.cache[url]
+	if !ok {
+		pending = &pendingFetch{done: make(chan struct{})}
+		f.cache[url] = pending
+	}
+	f.mu.Unlock()
+
+	if !ok {
+		pending.result = f.download(ctx, url)
+		close(pending.done)
+		return pending.result
+	}
+	select {
+	case <-pending.done:
+		return pending.result
+	case <-ctx.Done():
+		return &fetchedImage{Err: ctx.Err()}
+	}
+}
+
+// download โหลดรูปจาก cache บนดิสก์หรือ network พร้อม retry แบบ exponential backoff
+func (f *imageFetcher) download(ctx context.Context, url string) *fetchedImage {
+	// data: URI และไฟล์ในเครื่องไม่ผ่าน network และ cache บนดิสก์
+	if !isRemoteImage(url) {
+		f.logf("📁 Loading local image: %s\n", displayURL(url))
+		return loadLocalImage(url, f.assetsDir)
+	}
+
+	var cached *fetchedImage
+	if f.disk != nil {
+		if e, data, ok := f.disk.load(url); ok {
+			cached = &fetchedImage{
+				Data:         data,
+				ContentType:  e.ContentType,
+				ETag:         e.ETag,
+				LastModified: e.LastModified,
+			}
+		}
+	}
+
+	if f.offline {
+		if cached == nil {
+			atomic.AddInt64(&f.misses, 1)
+			return &fetchedImage{Err: fmt.Errorf("offline: %s is not in the image cache", url)}
+		}
+		atomic.AddInt64(&f.hits, 1)
+		f.logf("💾 Cache hit (offline): %s\n", url)
+		return cached
+	}
+
+	f.logf("🔄 Downloading image: %s\n", url)
+
+	backoff := f.backoff
+	var result *fetchedImage
+	for attempt := 0; attempt <= f.retries; attempt++ {
+		if attempt > 0 {
+			f.logf("🔁 Retry %d/%d for %s: %v\n", attempt, f.retries, url, result.Err)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				atomic.AddInt64(&f.misses, 1)
+				return &fetchedImage{Err: ctx.Err()}
+			}
+			backoff *= 2
+		}
+
+		var retryable bool
+		result, retryable = f.get(ctx, url, cached)
+		if result.Err == nil || !retryable || ctx.Err() != nil {
+			break
+		}
+	}
+
+	switch {
+	case result.NotModified && cached != nil:
+		atomic.AddInt64(&f.hits, 1)
+		f.logf("💾 Cache hit (not modified): %s\n", url)
+		return cached
+
+	case result.Err != nil && cached != nil && ctx.Err() == nil:
+		// server มีปัญหาแต่ยังมีรูปเก่าใน cache
+		atomic.AddInt64(&f.hits, 1)
+		f.logf("⚠️ Using cached copy of %s: %v\n", url, result.Err)
+		return cached
+	}
+
+	// ทุก request ที่ไม่ได้ใช้รูปจาก cache นับเป็น miss ทั้งที่สำเร็จและล้มเหลว
+	atomic.AddInt64(&f.misses, 1)
+	if result.Err != nil || f.disk == nil {
+		return result
+	}
+	// เก็บลง cache เฉพาะข้อมูลที่เป็นรูปจริง (กันหน้า error HTML ที่ตอบ 200)
+	if _, _, err := detectImageFormat(result.Data); err != nil {
+		f.logf("⚠️ Not caching %s: %v\n", url, err)
+		return result
+	}
+	entry := cacheEntry{
+		URL:          url,
+		ContentType:  result.ContentType,
+		ETag:         result.ETag,
+		LastModified: result.LastModified,
+		StoredAt:     time.Now().UTC(),
+	}
+	if err := f.disk.store(entry, result.Data); err != nil {
+		f.logf("⚠️ Cannot write image cache for %s: %v\n", url, err)
+	}
+	return result
+}
+
+// get ส่ง request หนึ่งครั้งภายใต้ timeout ต่อ request
+// คืนค่า retryable = true ถ้า error เป็นแบบชั่วคราว (network, 5xx, 429)
+// ถ้ามี cached จะส่ง If-None-Match / If-Modified-Since เพื่อ revalidate
+func (f *imageFetcher) get(ctx context.Context, url string, cached *fetchedImage) (result *fetchedImage, retryable bool) {
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	// //host/img.png ไม่มี scheme ใช้ https เหมือน browser บนหน้า https
+	if strings.HasPrefix(url, "//") {
+		url = "https:" + url
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return &fetchedImage{Err: err}, false
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0")
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return &fetchedImage{Err: err}, true
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return &fetchedImage{NotModified: true}, false
+	}
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("unexpected status: %s", resp.Status)
+		return &fetchedImage{Err: err}, resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	}
+
+	tooLarge := fmt.Errorf("image larger than %d bytes", f.maxSize)
+	if resp.ContentLength > f.maxSize {
+		return &fetchedImage{Err: tooLarge}, false
+	}
+	// อ่านเกิน maxSize หนึ่ง byte เพื่อรู้ว่ารูปใหญ่เกินโดยไม่ต้องอ่านทั้งหมด (server อาจไม่ส่ง Content-Length)
+	data, err := io.ReadAll(io.LimitReader(resp.Body, f.maxSize+1))
+	if err != nil {
+		return &fetchedImage{Err: err}, true
+	}
+	if int64(len(data)) > f.maxSize {
+		return &fetchedImage{Err: tooLarge}, false
+	}
+
+	return &fetchedImage{
+		Data:         data,
+		ContentType:  resp.Header.Get("Content-Type"),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}, false
+}
@@ -0,0 +1,303 @@
+package exportdocx
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestImageFetcherDedupesAndBoundsWorkers(t *testing.T) {
+	data := pngBytes(t, 10, 10)
+	var mu sync.Mutex
+	hits := make(map[string]int)
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	var urls []string
+	for i := 0; i < 3; i++ {
+		for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+			urls = append(urls, server.URL+"/"+name+".png")
+		}
+	}
+
+	fetcher := newImageFetcher(Options{DownloadWorkers: 2}, func(string, ...interface{}) {})
+	fetcher.prefetch(context.Background(), urls)
+
+	if len(hits) != 6 {
+		t.Fatalf("downloaded %d distinct URLs, want 6", len(hits))
+	}
+	for path, n := range hits {
+		if n != 1 {
+			t.Errorf("%s downloaded %d times, want 1", path, n)
+		}
+	}
+	if max := atomic.LoadInt32(&maxInFlight); max > 2 {
+		t.Errorf("max concurrent requests = %d, want <= 2", max)
+	}
+	for _, url := range urls {
+		if result := fetcher.fetch(context.Background(), url); result.Err != nil || !bytes.Equal(result.Data, data) {
+			t.Errorf("fetch(%s) = %v, want cached image", url, result.Err)
+		}
+	}
+}
+
+func TestImageFetcherRetriesTransientErrors(t *testing.T) {
+	data := pngBytes(t, 10, 10)
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	fetcher := newImageFetcher(Options{DownloadRetries: 2, RetryBackoff: time.Millisecond}, func(string, ...interface{}) {})
+	result := fetcher.fetch(context.Background(), server.URL+"/img.png")
+	if result.Err != nil {
+		t.Fatalf("fetch: %v", result.Err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("server called %d times, want 3", got)
+	}
+}
+
+func TestImageFetcherZeroRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// ค่าศูนย์ของ DownloadRetries คือไม่ลองใหม่
+	fetcher := newImageFetcher(Options{RetryBackoff: time.Millisecond}, func(string, ...interface{}) {})
+	if result := fetcher.fetch(context.Background(), server.URL+"/busy.png"); result.Err == nil {
+		t.Fatal("fetch succeeded, want error")
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("server called %d times, want 1", got)
+	}
+}
+
+func TestImageFetcherDoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	fetcher := newImageFetcher(Options{DownloadRetries: 3, RetryBackoff: time.Millisecond}, func(string, ...interface{}) {})
+	if result := fetcher.fetch(context.Background(), server.URL+"/missing.png"); result.Err == nil {
+		t.Fatal("fetch succeeded, want error")
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("server called %d times, want 1", got)
+	}
+}
+
+func TestImageFetcherSharesInFlightDownload(t *testing.T) {
+	data := pngBytes(t, 10, 10)
+	var calls int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+		}
+		<-release
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	fetcher := newImageFetcher(Options{}, func(string, ...interface{}) {})
+	url := server.URL + "/shared.png"
+
+	// ผู้เรียกคนที่สองมาระหว่างที่คนแรกยังโหลดอยู่ ต้องรอผลเดียวกันแทนการส่ง request ใหม่
+	results := make(chan *fetchedImage, 2)
+	go func() { results <- fetcher.fetch(context.Background(), url) }()
+	<-started
+	go func() { results <- fetcher.fetch(context.Background(), url) }()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	first, second := <-results, <-results
+	if first != second || first.Err != nil {
+		t.Errorf("concurrent fetches got different results (%v, %v)", first.Err, second.Err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("server called %d times, want 1", got)
+	}
+}
+
+func TestImageFetcherRejectsOversizedImages(t *testing.T) {
+	data := pngBytes(t, 40, 40)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/chunked.png" {
+			// ไม่มี Content-Length ต้องหยุดอ่านที่ขนาดสูงสุด
+			w.Header().Set("Transfer-Encoding", "chunked")
+			w.Write(data[:len(data)/2])
+			w.(http.Flusher).Flush()
+			w.Write(data[len(data)/2:])
+			return
+		}
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	limit := int64(len(data) - 1)
+	fetcher := newImageFetcher(Options{MaxImageSize: limit}, func(string, ...interface{}) {})
+	for _, path := range []string{"/sized.png", "/chunked.png"} {
+		result := fetcher.fetch(context.Background(), server.URL+path)
+		if result.Err == nil || !strings.Contains(result.Err.Error(), "larger than") {
+			t.Errorf("%s: got %v, want size error", path, result.Err)
+		}
+	}
+
+	fetcher = newImageFetcher(Options{MaxImageSize: int64(len(data))}, func(string, ...interface{}) {})
+	if result := fetcher.fetch(context.Background(), server.URL+"/exact.png"); result.Err != nil {
+		t.Errorf("image at the size limit: %v", result.Err)
+	}
+}
+
+func TestImageFetcherTimesOutSlowRequests(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	fetcher := newImageFetcher(Options{DownloadTimeout: 50 * time.Millisecond}, func(string, ...interface{}) {})
+
+	start := time.Now()
+	if result := fetcher.fetch(context.Background(), server.URL+"/slow.png"); result.Err == nil {
+		t.Fatal("fetch succeeded, want timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("fetch took %v, want it to stop at the per-request timeout", elapsed)
+	}
+}
+
+func TestExportImageOrderIsDeterministic(t *testing.T) {
+	data := pngBytes(t, 10, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// รูปแรกตอบช้าที่สุด เพื่อให้ลำดับที่โหลดเสร็จต่างจากลำดับในเอกสาร
+		if strings.HasSuffix(r.URL.Path, "first.png") {
+			time.Sleep(50 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	chapters := []ChapterData{
+		{ID: "1", Chapter: "1", Body: `<p><img src="` + server.URL + `/first.png"></p><p><img src="` + server.URL + `/second.png"></p>`},
+		{ID: "2", Chapter: "2", Body: `<p><img src="` + server.URL + `/first.png"></p><p><img src="` + server.URL + `/third.png"></p>`},
+	}
+
+	var buf bytes.Buffer
+	if err := Export(context.Background(), chapters, &buf, Options{DownloadWorkers: 4}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	rels := readZipFiles(t, buf.Bytes())["word/_rels/document.xml.rels"]
+
+	want := []string{
+		`Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/image1_`,
+		`Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/image2_`,
+		`Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/image3_`,
+	}
+	for _, w := range want {
+		if !strings.Contains(rels, w) {
+			t.Errorf("rels missing %s\n%s", w, rels)
+		}
+	}
+	if strings.Count(rels, `/relationships/image"`) != 3 {
+		t.Errorf("duplicate URL embedded twice:\n%s", rels)
+	}
+}
+
+func TestExportLogFromConcurrentWorkers(t *testing.T) {
+	// bytes.Buffer ไม่ปลอดภัยกับหลาย goroutine ถ้า logf ไม่ล็อก go test -race จะพบ data race
+	data := pngBytes(t, 10, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	var body strings.Builder
+	for i := range 32 {
+		body.WriteString(`<p><img src="` + server.URL + "/" + strconv.Itoa(i) + `.png"></p>`)
+	}
+	var log bytes.Buffer
+	chapters := []ChapterData{{ID: "1", Chapter: "1", Body: body.String()}}
+	if err := Export(context.Background(), chapters, &bytes.Buffer{}, Options{Log: &log, DownloadWorkers: 8}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if got := strings.Count(log.String(), "🔄 Downloading image:"); got != 32 {
+		t.Errorf("log has %d download lines, want 32:\n%s", got, log.String())
+	}
+}
+
+type recordingTransport struct {
+	mu   sync.Mutex
+	urls []string
+	body []byte
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	rt.urls = append(rt.urls, req.URL.String())
+	rt.mu.Unlock()
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"image/png"}},
+		Body:       io.NopCloser(bytes.NewReader(rt.body)),
+		Request:    req,
+	}, nil
+}
+
+func TestImageFetcherProtocolRelativeURL(t *testing.T) {
+	transport := &recordingTransport{body: pngBytes(t, 4, 4)}
+	fetcher := newImageFetcher(Options{HTTPClient: &http.Client{Transport: transport}}, func(string, ...interface{}) {})
+
+	result := fetcher.fetch(context.Background(), "//cdn.example.com/img/a.png")
+	if result.Err != nil {
+		t.Fatalf("fetch: %v", result.Err)
+	}
+	if len(transport.urls) != 1 || transport.urls[0] != "https://cdn.example.com/img/a.png" {
+		t.Errorf("requested %q, want one request to https://cdn.example.com/img/a.png", transport.urls)
+	}
+}
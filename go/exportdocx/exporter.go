@@ -0,0 +1,562 @@
+// Package exportdocx แปลงข้อมูลบท (ชื่อบท + HTML body) เป็นไฟล์ DOCX
+package exportdocx
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-export-docx/thaiseg"
+)
+
+// Options กำหนดการทำงานของ Exporter
+type Options struct {
+	// Log รับข้อความสถานะระหว่าง export (nil = ไม่แสดง)
+	Log io.Writer
+
+	// HTTPClient ใช้ดาวน์โหลดรูปภาพ (nil = http.DefaultClient)
+	HTTPClient *http.Client
+	// DownloadWorkers จำนวนรูปที่ดาวน์โหลดพร้อมกัน (0 = 8)
+	DownloadWorkers int
+	// DownloadTimeout timeout ต่อหนึ่ง request (0 = 30 วินาที)
+	DownloadTimeout time.Duration
+	// DownloadRetries จำนวนครั้งที่ลองใหม่เมื่อโหลดไม่สำเร็จ (0 = ไม่ลองใหม่)
+	DownloadRetries int
+	// RetryBackoff ระยะรอก่อนลองใหม่ครั้งแรก และเพิ่มเป็นสองเท่าทุกครั้ง (0 = 500ms)
+	RetryBackoff time.Duration
+	// MaxImageSize ขนาดสูงสุดของรูปที่ดาวน์โหลดเป็น byte รูปที่ใหญ่กว่านี้ถือว่าโหลดไม่สำเร็จ (0 = 20 MB)
+	MaxImageSize int64
+
+	// CacheDir โฟลเดอร์เก็บรูปที่ดาวน์โหลดแล้วข้ามการ export ("" = ไม่ใช้ cache)
+	CacheDir string
+	// Offline ใช้เฉพาะรูปใน CacheDir โดยไม่เรียก network
+	Offline bool
+
+	// Title ชื่อหนังสือ ใช้ในหน้าปกและ docProps/core.xml
+	Title string
+	// Author ชื่อผู้แต่งที่แสดงในหน้าปก
+	Author string
+	// TitlePage ใส่หน้าปก (Title และ Author) ที่ต้นเอกสาร ถ้าทั้งสองค่าว่างจะไม่มีหน้าปก
+	TitlePage bool
+	// TOC ใส่สารบัญ (TOC field) ก่อนบทแรก พร้อมรายการบทที่สร้างไว้ล่วงหน้า
+	TOC bool
+	// TOCTitle หัวข้อของสารบัญ ("" = "สารบัญ")
+	TOCTitle string
+
+	// Header และ Footer คือหัวและท้ายกระดาษของทุกหน้า (ค่าว่าง = ไม่มี)
+	Header HeaderFooter
+	Footer HeaderFooter
+	// DifferentFirstPage ใช้ FirstHeader/FirstFooter กับหน้าแรกแทน (ค่าว่าง = หน้าแรกไม่มีหัว/ท้ายกระดาษ)
+	DifferentFirstPage bool
+	FirstHeader        HeaderFooter
+	FirstFooter        HeaderFooter
+	// EvenAndOddHeaders ใช้ EvenHeader/EvenFooter กับหน้าคู่ และ Header/Footer กับหน้าคี่
+	EvenAndOddHeaders bool
+	EvenHeader        HeaderFooter
+	EvenFooter        HeaderFooter
+
+	// Typography ฟอนต์ ขนาด ภาษา และการจัดแนวย่อหน้า (ค่าว่าง = DefaultTypography)
+	// ใช้ ThaiTypography() กับเอกสารภาษาไทย
+	Typography Typography
+	// ThaiWordBreaks ใส่ zero-width space (U+200B) ระหว่างคำไทยด้วยพจนานุกรมของ package thaiseg
+	// ช่วยให้โปรแกรมที่ไม่มีตัวตัดคำไทยตัดบรรทัดตรงขอบคำ
+	ThaiWordBreaks bool
+
+	// Page ขนาดหน้ากระดาษและขอบ (ค่าว่าง = A4 ขอบ 1 นิ้ว)
+	Page PageSetup
+
+	// ChapterBreak วิธีขึ้นบทใหม่ ("" = ChapterBreakPage)
+	ChapterBreak ChapterBreak
+	// RestartPageNumbers เริ่มเลขหน้า 1 ใหม่ทุกบท (ใช้กับ ChapterBreakNextPage/ChapterBreakOddPage)
+	// ถ้าไม่เปิด เลขหน้าจะต่อเนื่องทุกบท โดยเริ่มที่ 1 ตั้งแต่บทแรก (ไม่นับหน้าปกและสารบัญ)
+	RestartPageNumbers bool
+
+	// ReferenceDocx path ของ DOCX ต้นแบบ ใช้ styles, theme, ฟอนต์, numbering และ settings ของไฟล์นั้นแทนค่าของ converter
+	// style ที่ converter ใช้แต่ไม่มีในไฟล์ต้นแบบ (เช่น Heading1, TOC1) จะถูกเติมให้
+	// docDefaults มาจากไฟล์ต้นแบบ Typography จึงมีผลแค่การจัดแนวของ BodyText ที่เติมให้
+	ReferenceDocx string
+
+	// StyleRules ผูก class และ tag ของ HTML กับ paragraph style, character style หรือ direct formatting
+	// (nil = DefaultStyleRules, slice ว่าง = ไม่ใช้ rule) อ่านจากไฟล์ JSON ได้ด้วย LoadStyleRules
+	StyleRules []StyleRule
+
+	// AssetsDir โฟลเดอร์ที่ใช้หารูปซึ่งอ้างด้วย path แบบ relative หรือ file://
+	// อ่านได้เฉพาะไฟล์ที่อยู่ในโฟลเดอร์นี้ ("" = ไม่อ่านไฟล์ในเครื่อง ใช้ได้แค่ data: URI และรูปจาก HTTP)
+	AssetsDir string
+}
+
+// ChapterBreak คือวิธีแบ่งบท
+type ChapterBreak string
+
+const (
+	// ChapterBreakPage คั่นบทด้วย page break (ทั้งเอกสารเป็น section เดียว)
+	ChapterBreakPage ChapterBreak = "page"
+	// ChapterBreakNextPage จบแต่ละบทด้วย section break บทถัดไปเริ่มหน้าใหม่
+	ChapterBreakNextPage ChapterBreak = "nextPage"
+	// ChapterBreakOddPage จบแต่ละบทด้วย section break บทถัดไปเริ่มหน้าคี่ (หน้าขวา)
+	ChapterBreakOddPage ChapterBreak = "oddPage"
+)
+
+// Summary สรุปผลการ export ครั้งล่าสุด
+type Summary struct {
+	Chapters    int
+	Images      int
+	CacheHits   int // รูปที่ใช้จาก cache บนดิสก์ (รวม 304 Not Modified)
+	CacheMisses int // รูปที่ต้องดาวน์โหลดใหม่หรือไม่มีใน cache
+}
+
+// Exporter สร้างไฟล์ DOCX โดยเก็บ state ของรูปภาพและ relationship ID ไว้เอง
+// Exporter หนึ่งตัวใช้ export ได้ทีละครั้ง ถ้าต้องการ export พร้อมกันให้สร้างหลายตัว
+type Exporter struct {
+	opts Options
+	log  io.Writer
+	// logMu กัน worker ที่ดาวน์โหลดรูปพร้อมกันเขียน Options.Log ชนกัน
+	logMu sync.Mutex
+
+	fetcher         *imageFetcher
+	images          []ImageInfo // รูปภาพที่ฝังในเอกสาร เรียงตามลำดับที่พบ
+	imageCounter    int
+	drawingCounter  int
+	relCounter      int
+	bookmarkID      int
+	anchorCounter   int
+	hyperlinkRels   []Relationship    // ลิงก์ภายนอก เรียงตามลำดับที่พบ
+	hyperlinkRelIDs map[string]string // URL -> relationship ID
+	anchors         map[string]string // id ใน HTML ของบทปัจจุบัน -> ชื่อ bookmark
+	anchorLinks     []anchorLink      // ลิงก์ "#id" ของบทปัจจุบันที่ยังไม่ผูกกับ bookmark
+	lists           []*listNumbering  // list ทั้งหมดในเอกสาร (w:num ใน numbering.xml)
+	numbering       numberingOffsets  // ID ที่ numbering.xml ของไฟล์ต้นแบบใช้ไปแล้ว
+	cellWidth       int               // ความกว้างของเนื้อหาในเซลล์ที่กำลังแปลง (twips, 0 = ไม่ได้อยู่ในตาราง)
+	hfParts         []headerFooterPart
+	segmenter       *thaiseg.Segmenter // nil = ไม่ตัดคำ
+	reference       *referenceDocx     // nil = ไม่ใช้ไฟล์ต้นแบบ
+	styleRules      styleRules         // Options.StyleRules ที่แยก selector แล้ว
+
+	summary Summary
+}
+
+// New สร้าง Exporter ใหม่
+func New(opts Options) *Exporter {
+	e := &Exporter{opts: opts, log: opts.Log}
+	if e.log == nil {
+		e.log = io.Discard
+	}
+	if opts.ThaiWordBreaks {
+		e.segmenter = thaiseg.Default()
+	}
+	// selector ที่ผิดถูกรายงานตอน Export
+	e.styleRules, _ = compileStyleRules(opts.StyleRules)
+	e.reset()
+	return e
+}
+
+// Export แปลง chapters เป็น DOCX แล้วเขียนลง w
+func Export(ctx context.Context, chapters []ChapterData, w io.Writer, opts Options) error {
+	return New(opts).Export(ctx, chapters, w)
+}
+
+// Export แปลง chapters เป็น DOCX แล้วเขียนลง w
+func (e *Exporter) Export(ctx context.Context, chapters []ChapterData, w io.Writer) error {
+	switch e.opts.ChapterBreak {
+	case "", ChapterBreakPage, ChapterBreakNextPage, ChapterBreakOddPage:
+	default:
+		return fmt.Errorf("unknown chapter break %q", e.opts.ChapterBreak)
+	}
+	if _, err := compileStyleRules(e.opts.StyleRules); err != nil {
+		return err
+	}
+	page := e.pageSetup()
+	if *page.Top < 0 || *page.Bottom < 0 || *page.Inside < 0 || *page.Outside < 0 || page.Gutter < 0 {
+		return fmt.Errorf("page margins must not be negative")
+	}
+	if e.textWidthPx() <= 0 {
+		return fmt.Errorf("page margins (%d+%d+%d twips) leave no room on a %d twips wide page", *page.Inside, *page.Outside, page.Gutter, page.Width)
+	}
+
+	e.reset()
+	if e.opts.ReferenceDocx != "" {
+		reference, err := loadReferenceDocx(e.opts.ReferenceDocx)
+		if err != nil {
+			return fmt.Errorf("reference docx %s: %w", e.opts.ReferenceDocx, err)
+		}
+		e.reference = reference
+		e.numbering = reference.numberingOffsets()
+		e.logf("🎨 Using styles from reference %s\n", e.opts.ReferenceDocx)
+	}
+
+	zipWriter := zip.NewWriter(w)
+
+	if err := e.writeParts(ctx, zipWriter, chapters); err != nil {
+		zipWriter.Close()
+		return err
+	}
+	if err := zipWriter.Close(); err != nil {
+		return err
+	}
+
+	e.summary = Summary{
+		Chapters:    len(chapters),
+		Images:      len(e.images),
+		CacheHits:   int(atomic.LoadInt64(&e.fetcher.hits)),
+		CacheMisses: int(atomic.LoadInt64(&e.fetcher.misses)),
+	}
+	return nil
+}
+
+// Summary คืนค่าสรุปของการ export ครั้งล่าสุด
+func (e *Exporter) Summary() Summary {
+	return e.summary
+}
+
+// reset ล้าง state ของรูปภาพก่อน export ครั้งใหม่
+func (e *Exporter) reset() {
+	e.fetcher = newImageFetcher(e.opts, e.logf)
+	e.images = nil
+	e.imageCounter = 1
+	e.drawingCounter = 0
+	e.relCounter = 2 // เริ่มจาก 2 เพราะ rId1 ใช้กับ styles.xml
+	e.bookmarkID = 0
+	e.anchorCounter = 0
+	e.hyperlinkRels = nil
+	e.hyperlinkRelIDs = make(map[string]string)
+	e.anchors = make(map[string]string)
+	e.anchorLinks = nil
+	e.lists = nil
+	e.numbering = numberingOffsets{}
+	e.hfParts = nil
+	e.reference = nil
+	e.summary = Summary{}
+}
+
+// nextRelID จัดสรร relationship ID ถัดไปของ document.xml.rels
+func (e *Exporter) nextRelID() string {
+	relID := fmt.Sprintf("rId%d", e.relCounter)
+	e.relCounter++
+	return relID
+}
+
+// pageSectPr คืนการตั้งค่าหน้ากระดาษของเอกสารจาก Options.Page
+// ขอบซ้ายคือขอบด้านสัน เมื่อเปิด mirrorMargins ใน settings.xml Word จะสลับให้ในหน้าคู่
+func (e *Exporter) pageSectPr() SectPr {
+	page := e.pageSetup()
+	sectPr := SectPr{
+		PgSz: PgSz{W: strconv.Itoa(page.Width), H: strconv.Itoa(page.Height)},
+		PgMar: PgMar{
+			Top:    strconv.Itoa(*page.Top),
+			Right:  strconv.Itoa(*page.Outside),
+			Bottom: strconv.Itoa(*page.Bottom),
+			Left:   strconv.Itoa(*page.Inside),
+			Header: "720",
+			Footer: "720",
+			Gutter: strconv.Itoa(page.Gutter),
+		},
+	}
+	if page.Width > page.Height {
+		sectPr.PgSz.Orient = "landscape"
+	}
+	return sectPr
+}
+
+// textWidthTwips คืนความกว้างของพื้นที่ข้อความ (หน้ากระดาษลบขอบซ้ายขวาและ gutter) เป็น twips
+func (e *Exporter) textWidthTwips() int {
+	page := e.pageSetup()
+	return page.Width - *page.Inside - *page.Outside - page.Gutter
+}
+
+// textWidthPx คืนความกว้างของพื้นที่ข้อความเป็น px ที่ 96 dpi
+func (e *Exporter) textWidthPx() int {
+	return e.textWidthTwips() / twipsPerPx
+}
+
+// contentWidthTwips คืนความกว้างที่เนื้อหาใช้ได้ (ความกว้างของเซลล์เมื่ออยู่ในตาราง)
+func (e *Exporter) contentWidthTwips() int {
+	if e.cellWidth > 0 {
+		return e.cellWidth
+	}
+	return e.textWidthTwips()
+}
+
+func (e *Exporter) contentWidthPx() int {
+	return e.contentWidthTwips() / twipsPerPx
+}
+
+// 1 px (96 dpi) = 15 twips
+const twipsPerPx = 15
+
+// logf เขียนข้อความสถานะลง Options.Log (เรียกจากหลาย goroutine ได้)
+func (e *Exporter) logf(format string, args ...interface{}) {
+	e.logMu.Lock()
+	defer e.logMu.Unlock()
+	fmt.Fprintf(e.log, format, args...)
+}
+
+func (e *Exporter) writeParts(ctx context.Context, zipWriter *zip.Writer, chapters []ChapterData) error {
+	// parse HTML ทุกบทก่อนเขียน part ใดๆ เพราะบาง part (numbering.xml) ขึ้นกับเนื้อหา
+	chapterSegments := make([][]contentSegment, len(chapters))
+	for i, chapter := range chapters {
+		chapterSegments[i] = e.parseChapterHTML(chapter.Body)
+	}
+
+	// สร้างไฟล์ที่จำเป็นใน DOCX
+	overrides := append(e.headerFooterContentTypes(), e.referenceContentTypes()...)
+	overrides = append(overrides, e.numberingContentTypes()...)
+	if err := createContentTypes(zipWriter, overrides); err != nil {
+		return err
+	}
+	if err := createRels(zipWriter); err != nil {
+		return err
+	}
+	if err := createApp(zipWriter); err != nil {
+		return err
+	}
+	if err := createCore(zipWriter, e.opts.Title); err != nil {
+		return err
+	}
+	if err := e.writeStyles(zipWriter); err != nil {
+		return err
+	}
+	// ให้ Word อัปเดต field (เช่น TOC) ตอนเปิดไฟล์
+	settings := documentSettings{
+		UpdateFields:      e.opts.TOC,
+		EvenAndOddHeaders: e.opts.EvenAndOddHeaders,
+		MirrorMargins:     e.opts.Page.MirrorMargins,
+	}
+	if e.reference != nil && e.reference.settings != nil {
+		if err := writePart(zipWriter, "word/settings.xml", e.reference.mergeSettings(settings)); err != nil {
+			return err
+		}
+	} else if err := createSettings(zipWriter, settings); err != nil {
+		return err
+	}
+	if err := e.writeNumbering(zipWriter); err != nil {
+		return err
+	}
+	if err := e.writeReferenceParts(zipWriter); err != nil {
+		return err
+	}
+	if err := e.writeHeaderFooters(zipWriter); err != nil {
+		return err
+	}
+
+	// สร้าง document.xml จากข้อมูลบท
+	if err := e.createDocument(ctx, zipWriter, chapters, chapterSegments); err != nil {
+		return err
+	}
+
+	// สร้าง document.xml.rels สำหรับรูปภาพ
+	if err := e.createDocumentRels(zipWriter); err != nil {
+		return err
+	}
+
+	// เพิ่มรูปภาพลงใน ZIP
+	return e.addImagesToZip(zipWriter)
+}
+
+func (e *Exporter) createDocument(ctx context.Context, zipWriter *zip.Writer, chapters []ChapterData, chapterSegments [][]contentSegment) error {
+	w, err := zipWriter.Create("word/document.xml")
+	if err != nil {
+		return err
+	}
+
+	doc := Document{
+		Xmlns:    "http://schemas.openxmlformats.org/wordprocessingml/2006/main",
+		XmlnsR:   "http://schemas.openxmlformats.org/officeDocument/2006/relationships",
+		XmlnsWP:  "http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing",
+		XmlnsA:   "http://schemas.openxmlformats.org/drawingml/2006/main",
+		XmlnsPic: "http://schemas.openxmlformats.org/drawingml/2006/picture",
+		Body: Body{
+			Content: []interface{}{},
+		},
+	}
+
+	// ดาวน์โหลดรูปของทุกบทพร้อมกันก่อนสร้าง XML
+	var imageURLs []string
+	for _, segments := range chapterSegments {
+		imageURLs = append(imageURLs, figureURLs(segments)...)
+	}
+	e.fetcher.prefetch(ctx, imageURLs)
+
+	// bookmark ของหัวข้อแต่ละบท ใช้เป็นปลายทางของลิงก์ในสารบัญ
+	bookmarks := make([]string, len(chapters))
+	for i := range chapters {
+		bookmarks[i] = fmt.Sprintf("_TocChapter%d", i+1)
+	}
+
+	// หน้าปกและสารบัญ
+	var frontMatter []Paragraph
+	if e.opts.TitlePage {
+		frontMatter = append(frontMatter, e.titlePageParagraphs()...)
+	}
+	if e.opts.TOC {
+		if len(frontMatter) > 0 {
+			frontMatter = append(frontMatter, pageBreakParagraph())
+		}
+		frontMatter = append(frontMatter, e.tocParagraphs(chapters, bookmarks)...)
+	}
+	for _, para := range frontMatter {
+		doc.Body.Content = append(doc.Body.Content, para)
+	}
+	if len(frontMatter) > 0 {
+		if e.sectionPerChapter() {
+			// หน้าปกและสารบัญเป็น section ของตัวเอง
+			doc.Body.Content = endSection(doc.Body.Content, e.sectionProperties())
+		} else {
+			doc.Body.Content = append(doc.Body.Content, pageBreakParagraph())
+		}
+	}
+
+	// เพิ่มเนื้อหาแต่ละบท
+	for i, chapter := range chapters {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Page break ก่อนบทที่ 2 เป็นต้นไป
+		if i > 0 && !e.sectionPerChapter() {
+			doc.Body.Content = append(doc.Body.Content, pageBreakParagraph())
+		}
+
+		// หัวข้อบท - ใช้ชื่อบทจาก CSV
+		// ขนาด ตัวหนา ระยะห่าง และ outline level มาจาก style Heading1
+		title := Paragraph{
+			Props: &PPr{
+				// กำหนดให้เป็น Heading1 เพื่อโผล่ใน Navigation Pane
+				PStyle: &PStyle{Val: "Heading1"},
+			},
+			Runs: []Run{{
+				Text: &Text{
+					Value: chapter.Chapter,
+					Space: "preserve",
+				},
+			}},
+		}
+		title.BookmarkStart, title.BookmarkEnd = e.bookmark(bookmarks[i])
+		doc.Body.Content = append(doc.Body.Content, title)
+
+		// แปลง body content
+		bodyParagraphs := e.segmentsToParagraphs(ctx, chapterSegments[i])
+		e.resolveAnchorLinks()
+		for _, para := range bodyParagraphs {
+			doc.Body.Content = append(doc.Body.Content, para)
+		}
+
+		// section ของบทสุดท้ายคือ sectPr ของ body
+		if e.sectionPerChapter() && i < len(chapters)-1 {
+			doc.Body.Content = endSection(doc.Body.Content, e.chapterSectionProperties(i, len(frontMatter) > 0))
+		}
+	}
+
+	if e.sectionPerChapter() && len(chapters) > 0 {
+		doc.Body.SectPr = e.chapterSectionProperties(len(chapters)-1, len(frontMatter) > 0)
+	} else {
+		doc.Body.SectPr = e.sectionProperties()
+	}
+
+	// เขียน XML
+	xmlHeader := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+	var buf bytes.Buffer
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+
+	if _, err = w.Write([]byte(xmlHeader)); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, &buf)
+	return err
+}
+
+func (e *Exporter) createDocumentRels(zipWriter *zip.Writer) error {
+	w, err := zipWriter.Create("word/_rels/document.xml.rels")
+	if err != nil {
+		return err
+	}
+
+	relationships := Relationships{
+		Xmlns: "http://schemas.openxmlformats.org/package/2006/relationships",
+		Items: []Relationship{},
+	}
+
+	// เพิ่ม relationship สำหรับ styles.xml
+	relationships.Items = append(relationships.Items, Relationship{
+		Id:     "rId1",
+		Type:   "http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles",
+		Target: "styles.xml",
+	})
+
+	// เพิ่ม relationships สำหรับรูปภาพ
+	for _, img := range e.images {
+		relationships.Items = append(relationships.Items, Relationship{
+			Id:     img.RelId,
+			Type:   "http://schemas.openxmlformats.org/officeDocument/2006/relationships/image",
+			Target: "media/" + img.Filename,
+		})
+	}
+
+	// ลิงก์ภายนอก (ID จัดสรรตอนแปลง HTML)
+	relationships.Items = append(relationships.Items, e.hyperlinkRels...)
+
+	// header และ footer (ID จัดสรรตอนสร้าง sectPr)
+	for _, part := range e.headerFooterParts() {
+		relationships.Items = append(relationships.Items, Relationship{
+			Id:     part.RelId,
+			Type:   "http://schemas.openxmlformats.org/officeDocument/2006/relationships/" + part.Kind,
+			Target: part.Filename,
+		})
+	}
+
+	// settings.xml ได้ ID หลังสุด เพราะรูป ลิงก์ และ header/footer จัดสรร ID ไปแล้วตอนสร้าง document.xml
+	relationships.Items = append(relationships.Items, Relationship{
+		Id:     e.nextRelID(),
+		Type:   "http://schemas.openxmlformats.org/officeDocument/2006/relationships/settings",
+		Target: "settings.xml",
+	})
+
+	// theme, fontTable และ numbering จากไฟล์ต้นแบบ
+	relationships.Items = append(relationships.Items, e.referenceRelationships()...)
+	relationships.Items = append(relationships.Items, e.numberingRelationships()...)
+
+	// เขียน XML
+	xmlHeader := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+	var buf bytes.Buffer
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(relationships); err != nil {
+		return err
+	}
+
+	if _, err = w.Write([]byte(xmlHeader)); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, &buf)
+	return err
+}
+func (e *Exporter) addImagesToZip(zipWriter *zip.Writer) error {
+	for _, img := range e.images {
+		w, err := zipWriter.Create("word/media/" + img.Filename)
+		if err != nil {
+			return err
+		}
+
+		if _, err = w.Write(img.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package exportdocx
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func pngBytes(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func newImageServer(t *testing.T, data []byte) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// อ่านไฟล์ใน DOCX ที่ export แล้ว
+func readZipFiles(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = string(content)
+	}
+	return files
+}
+
+func TestExportWritesDocxParts(t *testing.T) {
+	chapters := []ChapterData{
+		{ID: "1", Chapter: "บทที่ 1", Body: "<p>Hello <b>world</b></p>"},
+		{ID: "2", Chapter: "บทที่ 2", Body: "<p>again</p>"},
+	}
+
+	var buf bytes.Buffer
+	if err := Export(context.Background(), chapters, &buf, Options{}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	files := readZipFiles(t, buf.Bytes())
+	for _, name := range []string{
+		"[Content_Types].xml",
+		"_rels/.rels",
+		"docProps/app.xml",
+		"docProps/core.xml",
+		"word/styles.xml",
+		"word/settings.xml",
+		"word/document.xml",
+		"word/_rels/document.xml.rels",
+	} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("missing part %s", name)
+		}
+	}
+	if doc := files["word/document.xml"]; !strings.Contains(doc, "บทที่ 2") || !strings.Contains(doc, "world") {
+		t.Errorf("document.xml does not contain chapter content")
+	}
+}
+
+func TestExportersDoNotShareImageState(t *testing.T) {
+	server := newImageServer(t, pngBytes(t, 40, 20))
+	chapters := []ChapterData{{
+		ID:      "1",
+		Chapter: "บทที่ 1",
+		Body:    `<p><img src="` + server.URL + `/a.png"></p><p><img src="` + server.URL + `/b.png"></p>`,
+	}}
+
+	const workers = 4
+	results := make([]map[string]string, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var buf bytes.Buffer
+			exporter := New(Options{})
+			if err := exporter.Export(context.Background(), chapters, &buf); err != nil {
+				t.Errorf("Export: %v", err)
+				return
+			}
+			if got := exporter.Summary().Images; got != 2 {
+				t.Errorf("Summary().Images = %d, want 2", got)
+			}
+			results[i] = readZipFiles(t, buf.Bytes())
+		}(i)
+	}
+	wg.Wait()
+
+	for i, files := range results {
+		if files == nil {
+			continue
+		}
+		rels := files["word/_rels/document.xml.rels"]
+		for _, want := range []string{`Id="rId2"`, `Id="rId3"`} {
+			if !strings.Contains(rels, want) {
+				t.Errorf("export %d: rels missing %s:\n%s", i, want, rels)
+			}
+		}
+		if strings.Count(rels, `/relationships/image"`) != 2 {
+			t.Errorf("export %d: relationship IDs leaked from another export:\n%s", i, rels)
+		}
+	}
+}
+
+func TestExportUsesNamedStyles(t *testing.T) {
+	server := newImageServer(t, pngBytes(t, 40, 20))
+	chapters := []ChapterData{{
+		ID:      "1",
+		Chapter: "บทที่ 1",
+		Body: `<p class="indent-a">เยื้อง <b>หนา</b> <i>เอียง</i> <b><i>ทั้งคู่</i></b></p>` +
+			`<figure><img src="` + server.URL + `/a.png"><figcaption>คำบรรยาย</figcaption></figure><p>ปกติ</p>`,
+	}}
+
+	var buf bytes.Buffer
+	if err := Export(context.Background(), chapters, &buf, Options{Title: "เรื่อง", Author: "ผู้เขียน", TitlePage: true}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	files := readZipFiles(t, buf.Bytes())
+
+	styles := files["word/styles.xml"]
+	for _, id := range []string{"Title", "Subtitle", "Caption", "BodyText", "BodyTextIndent", "Emphasis", "Strong"} {
+		if !strings.Contains(styles, `w:styleId="`+id+`"`) {
+			t.Errorf("styles.xml missing style %s", id)
+		}
+	}
+
+	doc := files["word/document.xml"]
+	for _, want := range []string{
+		`<w:pStyle w:val="Title">`,
+		`<w:pStyle w:val="Subtitle">`,
+		`<w:pStyle w:val="BodyTextIndent">`,
+		`<w:pStyle w:val="BodyText">`,
+		`<w:pStyle w:val="Caption">`,
+		`<w:rStyle w:val="Strong">`,
+		`<w:rStyle w:val="Emphasis">`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("document.xml missing %s", want)
+		}
+	}
+	// ไม่มี direct formatting ที่ style ทำหน้าที่แทนแล้ว
+	for _, unwanted := range []string{"<w:sz ", "<w:ind ", "<w:outlineLvl "} {
+		if strings.Contains(doc, unwanted) {
+			t.Errorf("document.xml should not contain direct formatting %s", unwanted)
+		}
+	}
+	// <b><i> ใช้ Strong กับตัวเอียงแบบ direct formatting
+	if !strings.Contains(compactXML(doc), `<w:rStylew:val="Strong"></w:rStyle><w:i></w:i><w:iCs></w:iCs>`) {
+		t.Errorf("nested <b><i> should combine Strong with direct italic")
+	}
+}
@@ -0,0 +1,207 @@
+package exportdocx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// HeaderFooter คือข้อความหัวหรือท้ายกระดาษ แบ่งเป็นชิดซ้าย กึ่งกลาง และชิดขวา
+// ข้อความใช้ placeholder ได้: {PAGE} เลขหน้า, {NUMPAGES} จำนวนหน้า,
+// {TITLE} ชื่อหนังสือ (Options.Title) และ {CHAPTER} ชื่อบทปัจจุบัน (STYLEREF Heading1)
+type HeaderFooter struct {
+	Left   string
+	Center string
+	Right  string
+}
+
+// IsZero คืน true ถ้าไม่มีข้อความเลย
+func (h HeaderFooter) IsZero() bool {
+	return h.Left == "" && h.Center == "" && h.Right == ""
+}
+
+// headerFooterPart คือ header*.xml หรือ footer*.xml หนึ่งไฟล์
+type headerFooterPart struct {
+	Kind     string // "header" หรือ "footer"
+	Type     string // "default", "first" หรือ "even" (w:type ของ headerReference)
+	Content  HeaderFooter
+	Filename string
+	RelId    string
+}
+
+type Hdr struct {
+	XMLName    xml.Name    `xml:"w:hdr"`
+	Xmlns      string      `xml:"xmlns:w,attr"`
+	XmlnsR     string      `xml:"xmlns:r,attr"`
+	Paragraphs []Paragraph `xml:"w:p"`
+}
+
+type Ftr struct {
+	XMLName    xml.Name    `xml:"w:ftr"`
+	Xmlns      string      `xml:"xmlns:w,attr"`
+	XmlnsR     string      `xml:"xmlns:r,attr"`
+	Paragraphs []Paragraph `xml:"w:p"`
+}
+
+var headerFooterFieldRegex = regexp.MustCompile(`\{(PAGE|NUMPAGES|TITLE|CHAPTER)\}`)
+
+// headerFooterParts คืน header/footer ที่ต้องเขียนตาม Options
+// ชื่อไฟล์ถูกกำหนดตั้งแต่ต้น แต่ relationship ID จะจัดสรรเมื่อสร้าง sectPr (หลังรูปภาพ)
+func (e *Exporter) headerFooterParts() []headerFooterPart {
+	if e.hfParts != nil {
+		return e.hfParts
+	}
+
+	candidates := []headerFooterPart{
+		{Kind: "header", Type: "default", Content: e.opts.Header},
+		{Kind: "header", Type: "first", Content: e.opts.FirstHeader},
+		{Kind: "header", Type: "even", Content: e.opts.EvenHeader},
+		{Kind: "footer", Type: "default", Content: e.opts.Footer},
+		{Kind: "footer", Type: "first", Content: e.opts.FirstFooter},
+		{Kind: "footer", Type: "even", Content: e.opts.EvenFooter},
+	}
+
+	parts := []headerFooterPart{}
+	counts := map[string]int{}
+	for _, part := range candidates {
+		if part.Content.IsZero() ||
+			(part.Type == "first" && !e.opts.DifferentFirstPage) ||
+			(part.Type == "even" && !e.opts.EvenAndOddHeaders) {
+			continue
+		}
+		counts[part.Kind]++
+		part.Filename = fmt.Sprintf("%s%d.xml", part.Kind, counts[part.Kind])
+		parts = append(parts, part)
+	}
+	e.hfParts = parts
+	return parts
+}
+
+// headerFooterParagraph สร้าง paragraph ของหัว/ท้ายกระดาษ
+// ข้อความกลางและขวาจัดตำแหน่งด้วย tab stop ที่กึ่งกลางและขอบขวาของพื้นที่ข้อความ
+func (e *Exporter) headerFooterParagraph(style string, content HeaderFooter) Paragraph {
+	width := e.textWidthTwips()
+	para := Paragraph{
+		Props: &PPr{
+			PStyle: &PStyle{Val: style},
+			Tabs: &Tabs{Tabs: []TabStop{
+				{Val: "center", Pos: strconv.Itoa(width / 2)},
+				{Val: "right", Pos: strconv.Itoa(width)},
+			}},
+		},
+	}
+
+	para.Runs = append(para.Runs, e.headerFooterRuns(content.Left)...)
+	if content.Center != "" || content.Right != "" {
+		para.Runs = append(para.Runs, Run{Tab: &Tab{}})
+		para.Runs = append(para.Runs, e.headerFooterRuns(content.Center)...)
+	}
+	if content.Right != "" {
+		para.Runs = append(para.Runs, Run{Tab: &Tab{}})
+		para.Runs = append(para.Runs, e.headerFooterRuns(content.Right)...)
+	}
+	return para
+}
+
+// headerFooterRuns แปลงข้อความที่มี placeholder เป็น runs และ field
+func (e *Exporter) headerFooterRuns(text string) []Run {
+	var runs []Run
+	appendText := func(s string) {
+		if s != "" {
+			runs = append(runs, Run{Text: &Text{Value: s, Space: "preserve"}})
+		}
+	}
+
+	last := 0
+	for _, m := range headerFooterFieldRegex.FindAllStringSubmatchIndex(text, -1) {
+		appendText(text[last:m[0]])
+		last = m[1]
+
+		switch text[m[2]:m[3]] {
+		case "PAGE":
+			runs = append(runs, fieldRuns(" PAGE ", "1")...)
+		case "NUMPAGES":
+			runs = append(runs, fieldRuns(" NUMPAGES ", "1")...)
+		case "CHAPTER":
+			runs = append(runs, fieldRuns(` STYLEREF "Heading 1" `, "")...)
+		case "TITLE":
+			appendText(e.opts.Title)
+		}
+	}
+	appendText(text[last:])
+	return runs
+}
+
+// fieldRuns สร้าง complex field พร้อมผลลัพธ์ที่แสดงก่อน Word อัปเดต field
+func fieldRuns(instruction, cached string) []Run {
+	runs := []Run{
+		{FldChar: &FldChar{FldCharType: "begin"}},
+		{InstrText: &InstrText{Value: instruction, Space: "preserve"}},
+		{FldChar: &FldChar{FldCharType: "separate"}},
+	}
+	if cached != "" {
+		runs = append(runs, Run{Text: &Text{Value: cached}})
+	}
+	return append(runs, Run{FldChar: &FldChar{FldCharType: "end"}})
+}
+
+// writeHeaderFooters เขียน header*.xml และ footer*.xml ลงใน ZIP
+func (e *Exporter) writeHeaderFooters(zipWriter *zip.Writer) error {
+	for _, part := range e.headerFooterParts() {
+		w, err := zipWriter.Create("word/" + part.Filename)
+		if err != nil {
+			return err
+		}
+
+		var root interface{}
+		if part.Kind == "header" {
+			root = Hdr{
+				Xmlns:      "http://schemas.openxmlformats.org/wordprocessingml/2006/main",
+				XmlnsR:     "http://schemas.openxmlformats.org/officeDocument/2006/relationships",
+				Paragraphs: []Paragraph{e.headerFooterParagraph("Header", part.Content)},
+			}
+		} else {
+			root = Ftr{
+				Xmlns:      "http://schemas.openxmlformats.org/wordprocessingml/2006/main",
+				XmlnsR:     "http://schemas.openxmlformats.org/officeDocument/2006/relationships",
+				Paragraphs: []Paragraph{e.headerFooterParagraph("Footer", part.Content)},
+			}
+		}
+
+		// เขียน XML
+		xmlHeader := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+		var buf bytes.Buffer
+		encoder := xml.NewEncoder(&buf)
+		encoder.Indent("", "  ")
+
+		if err := encoder.Encode(root); err != nil {
+			return err
+		}
+
+		if _, err = w.Write([]byte(xmlHeader)); err != nil {
+			return err
+		}
+
+		if _, err = io.Copy(w, &buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// headerFooterContentTypes คืน Override ของ header/footer สำหรับ [Content_Types].xml
+func (e *Exporter) headerFooterContentTypes() []contentTypeOverride {
+	var overrides []contentTypeOverride
+	for _, part := range e.headerFooterParts() {
+		overrides = append(overrides, contentTypeOverride{
+			PartName:    "/word/" + part.Filename,
+			ContentType: "application/vnd.openxmlformats-officedocument.wordprocessingml." + part.Kind + "+xml",
+		})
+	}
+	return overrides
+}
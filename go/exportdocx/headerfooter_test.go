@@ -0,0 +1,80 @@
+package exportdocx
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestExportHeadersAndFooters(t *testing.T) {
+	opts := Options{
+		Title:              "นิยาย",
+		Header:             HeaderFooter{Left: "{TITLE}", Right: "{CHAPTER}"},
+		Footer:             HeaderFooter{Center: "หน้า {PAGE} / {NUMPAGES}"},
+		DifferentFirstPage: true,
+		EvenAndOddHeaders:  true,
+		EvenHeader:         HeaderFooter{Left: "{CHAPTER}"},
+	}
+	chapters := []ChapterData{{ID: "1", Chapter: "บทที่ 1", Body: "<p>x</p>"}}
+
+	var buf bytes.Buffer
+	if err := Export(context.Background(), chapters, &buf, opts); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	files := readZipFiles(t, buf.Bytes())
+
+	header := files["word/header1.xml"]
+	for _, want := range []string{"<w:hdr", ">นิยาย</w:t>", `STYLEREF &#34;Heading 1&#34;`} {
+		if !strings.Contains(header, want) {
+			t.Errorf("header1.xml missing %s:\n%s", want, header)
+		}
+	}
+	if !strings.Contains(files["word/header2.xml"], "STYLEREF") {
+		t.Error("even header should be written as header2.xml")
+	}
+	footer := files["word/footer1.xml"]
+	for _, want := range []string{"<w:ftr", ">หน้า </w:t>", "> PAGE </w:instrText>", "> NUMPAGES </w:instrText>"} {
+		if !strings.Contains(footer, want) {
+			t.Errorf("footer1.xml missing %s:\n%s", want, footer)
+		}
+	}
+	// หน้าแรกไม่มีหัว/ท้ายกระดาษเพราะไม่ได้กำหนด FirstHeader/FirstFooter
+	if _, ok := files["word/header3.xml"]; ok {
+		t.Error("empty first-page header should not be written")
+	}
+
+	rels := files["word/_rels/document.xml.rels"]
+	doc := files["word/document.xml"]
+	for _, ref := range []struct{ kind, typ, target string }{
+		{"header", "default", "header1.xml"},
+		{"header", "even", "header2.xml"},
+		{"footer", "default", "footer1.xml"},
+	} {
+		relID := relationshipID(t, rels, ref.target)
+		want := `<w:` + ref.kind + `Reference w:type="` + ref.typ + `" r:id="` + relID + `">`
+		if !strings.Contains(doc, want) {
+			t.Errorf("sectPr missing %s", want)
+		}
+		if !strings.Contains(files["[Content_Types].xml"], `PartName="/word/`+ref.target+`"`) {
+			t.Errorf("content types missing %s", ref.target)
+		}
+	}
+	if !strings.Contains(doc, "<w:titlePg></w:titlePg>") {
+		t.Error("sectPr missing titlePg")
+	}
+	if !strings.Contains(files["word/settings.xml"], "<w:evenAndOddHeaders/>") {
+		t.Error("settings.xml missing evenAndOddHeaders")
+	}
+}
+
+// relationshipID หา Id ของ relationship ที่ชี้ไปยัง target
+func relationshipID(t *testing.T, rels, target string) string {
+	t.Helper()
+	i := strings.Index(rels, `Target="`+target+`"`)
+	if i < 0 {
+		t.Fatalf("no relationship for %s:\n%s", target, rels)
+	}
+	start := strings.LastIndex(rels[:i], `Id="`) + len(`Id="`)
+	return rels[start : start+strings.Index(rels[start:], `"`)]
+}
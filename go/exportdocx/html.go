@@ -0,0 +1,929 @@
+package exportdocx
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// โครงสร้างสำหรับ content segment
+type contentSegment struct {
+	Type   string       // "text", "inline", "figure" หรือ "table"
+	Node   *html.Node   // สำหรับ text (block element เช่น <p>)
+	Inline []*html.Node // สำหรับ inline content ที่ไม่มี block ครอบ
+	Figure figureRef    // สำหรับ figure
+	List   *listItem    // สำหรับ text/inline ที่มาจาก <li> (nil = ย่อหน้าปกติ)
+	Table  *tableRef    // สำหรับ table
+	Parent *html.Node   // block ที่ครอบ inline content (ใช้ style และ class ของ block นั้น)
+	Style  string       // paragraph style จาก block ที่ครอบ เช่น Quote ของ <blockquote> ("" = BodyText)
+}
+
+// figureRef เก็บข้อมูลของรูปที่พบใน HTML ก่อนดาวน์โหลด
+type figureRef struct {
+	URL            string
+	Align          string
+	Caption        string
+	ContainerStyle string // style ของ <figure> หรือ <p> ที่ครอบรูป
+	ImageStyle     string // style ของ <img>
+	WidthAttr      string // width attribute ของ <img>
+}
+
+// parse HTML ของหนึ่งบทเป็น segments (ยังไม่ดาวน์โหลดรูป)
+func (e *Exporter) parseChapterHTML(htmlContent string) []contentSegment {
+	// ขั้นตอนที่ 1: parse เป็น DOM tree ภายใต้ <body> (parser แปลง HTML entities ให้ครั้งเดียว)
+	root := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(htmlContent), root)
+	if err != nil {
+		e.logf("⚠️ HTML parse error: %v\n", err)
+		return nil
+	}
+	for _, n := range nodes {
+		root.AppendChild(n)
+	}
+
+	// ขั้นตอนที่ 2: แยก content เป็น segments โดยคำนึงถึงตำแหน่งของ figure
+	return e.parseContentWithFigures(root)
+}
+
+// รวบรวม URL ของรูปทั้งหมดตามลำดับที่พบ (รวมรูปในเซลล์ของตาราง)
+func figureURLs(segments []contentSegment) []string {
+	var urls []string
+	for _, segment := range segments {
+		switch segment.Type {
+		case "figure":
+			urls = append(urls, segment.Figure.URL)
+		case "table":
+			for _, row := range segment.Table.Rows {
+				for _, cell := range row.Cells {
+					urls = append(urls, figureURLs(cell.Segments)...)
+				}
+			}
+		}
+	}
+	return urls
+}
+
+// แปลงแต่ละ segment เป็น paragraph (รูปภาพถูกลงทะเบียนตามลำดับในเอกสาร)
+func (e *Exporter) segmentsToParagraphs(ctx context.Context, segments []contentSegment) []interface{} {
+	var paragraphs []interface{}
+
+	for _, segment := range segments {
+		switch segment.Type {
+		case "figure":
+			figure := segment.Figure
+			imageInfo, err := e.downloadImage(ctx, figure)
+			if err != nil {
+				e.logf("❌ Error downloading image %s: %v\n", displayURL(figure.URL), err)
+				continue
+			}
+			e.logf("📷 Added image with caption: %s\n", figure.Caption)
+
+			// สร้าง image paragraph พร้อม caption
+			imageParagraphs := e.createImageParagraph(imageInfo)
+			paragraphs = append(paragraphs, imageParagraphs...)
+		case "table":
+			paragraphs = append(paragraphs, e.createTable(ctx, segment.Table)...)
+		case "text", "inline":
+			var para Paragraph
+			switch {
+			case segment.Type == "inline":
+				// ข้อความที่ไม่อยู่ใน <p> ให้สร้าง paragraph เดียว
+				para = e.createParagraphFromInline(segment.Inline, segment.Parent)
+			case isEmptyOrOnlyNbsp(textContent(segment.Node)) && !containsElement(childNodes(segment.Node), atom.Br):
+				// จัดการ paragraph ว่างหรือมีแค่ &nbsp;
+				para = e.createEmptyParagraphWithAttributes(segment.Node)
+			default:
+				para = e.createParagraphFromHTML(segment.Node)
+			}
+			if segment.Style != "" {
+				restyleBodyParagraph(&para, segment.Style)
+			}
+			if segment.List != nil {
+				e.applyListItem(&para, segment.List)
+			}
+			paragraphs = append(paragraphs, para)
+		}
+	}
+
+	return paragraphs
+}
+
+var alignClassRegex = regexp.MustCompile(`\b(?:align-?)(left|center|right)\b`)
+
+// imageAlign อ่าน text-align (left, center, right) หรือ float (left, right) จาก style สำหรับจัดแนวรูป
+func imageAlign(style, property string) (string, bool) {
+	value, ok := cssProperty(style, property)
+	switch {
+	case !ok:
+		return "", false
+	case value == "left" || value == "right" || (value == "center" && property == "text-align"):
+		return value, true
+	}
+	return "", false
+}
+
+// ดึง alignment ของรูปโดยดูจาก context รอบๆ (p ที่ตามหลัง, container, img)
+func (e *Exporter) extractAlignFromImageWithContext(img, container, following *html.Node) string {
+	// 1. ตรวจสอบใน <p> tag ที่ตามหลัง figure
+	if following != nil {
+		if align, ok := imageAlign(getAttr(following, "style"), "text-align"); ok {
+			e.logf("🔍 Found text-align in following p tag: %s\n", align)
+			return align
+		}
+	}
+
+	// 2. ตรวจสอบใน figure tag
+	if container != nil && container.DataAtom == atom.Figure {
+		align := e.extractAlignFromFigure(container)
+		if align != "left" { // ถ้าไม่ใช่ค่าเริ่มต้น
+			return align
+		}
+	}
+
+	// 3. ตรวจสอบใน p และ img tag
+	return e.extractAlignFromImage(img, container)
+}
+
+// ฟังก์ชันดึง align จาก img และ p ที่ครอบอยู่
+func (e *Exporter) extractAlignFromImage(img, container *html.Node) string {
+	// 1. ตรวจสอบใน style attribute ของ p tag
+	if container != nil && container.DataAtom == atom.P {
+		styleContent := getAttr(container, "style")
+		if align, ok := imageAlign(styleContent, "text-align"); ok {
+			e.logf("🔍 Found text-align in p: %s\n", align)
+			return align
+		}
+	}
+	// 2. ตรวจสอบใน class attribute ของ img
+	if m := alignClassRegex.FindStringSubmatch(getAttr(img, "class")); len(m) > 1 {
+		e.logf("🔍 Found align class in img: %s\n", m[1])
+		return m[1]
+	}
+	// 3. ตรวจสอบใน style attribute ของ img
+	styleContent := getAttr(img, "style")
+	if align, ok := imageAlign(styleContent, "text-align"); ok {
+		e.logf("🔍 Found text-align in img: %s\n", align)
+		return align
+	}
+	if align, ok := imageAlign(styleContent, "float"); ok {
+		e.logf("🔍 Found float in img: %s\n", align)
+		return align
+	}
+	e.logf("🔍 No alignment found, using default: left\n")
+	return "left"
+}
+
+// ฟังก์ชันดึง align จาก figure tag
+func (e *Exporter) extractAlignFromFigure(figure *html.Node) string {
+	// 1. ตรวจสอบใน style attribute
+	styleContent := getAttr(figure, "style")
+	if align, ok := imageAlign(styleContent, "text-align"); ok {
+		e.logf("🔍 Found text-align: %s\n", align)
+		return align
+	}
+	// 2. ตรวจสอบใน class attribute
+	if m := alignClassRegex.FindStringSubmatch(getAttr(figure, "class")); len(m) > 1 {
+		e.logf("🔍 Found align class: %s\n", m[1])
+		return m[1]
+	}
+	// 3. ตรวจสอบ align attribute
+	switch align := strings.ToLower(getAttr(figure, "align")); align {
+	case "left", "center", "right":
+		e.logf("🔍 Found align attribute: %s\n", align)
+		return align
+	}
+	// 4. ตรวจสอบ float
+	if align, ok := imageAlign(styleContent, "float"); ok {
+		e.logf("🔍 Found float: %s\n", align)
+		return align
+	}
+	e.logf("🔍 No alignment found, using default: left\n")
+	return "left"
+}
+
+// แยก content เป็น segments โดยเดินตาม DOM tree เพื่อรักษาลำดับของ figure กับข้อความ
+func (e *Exporter) parseContentWithFigures(parent *html.Node) []contentSegment {
+	var segments []contentSegment
+	var inline []*html.Node
+
+	// inline content ใน block (เช่น ข้อความที่อยู่ใน <div> ปนกับ <p>) ใช้ style ของ block นั้น
+	var blockParent *html.Node
+	if isBlockElement(parent) {
+		blockParent = parent
+	}
+
+	// รวม inline nodes ที่อยู่นอก block element ให้เป็น segment เดียว
+	// <br> ตั้งแต่สองตัวติดกันแยกเป็นคนละ paragraph
+	flushInline := func() {
+		for _, group := range splitAtParagraphBreaks(inline) {
+			if img := findSoleImage(group); img != nil {
+				if segment, ok := e.createFigureSegment(img, nil, nil); ok {
+					segments = append(segments, segment)
+				}
+			} else if !isEmptyOrOnlyNbsp(textContentOf(group)) || containsElement(group, atom.Br) {
+				segments = append(segments, contentSegment{
+					Type:   "inline",
+					Inline: group,
+					Parent: blockParent,
+				})
+			}
+		}
+		inline = nil
+	}
+
+	for c := parent.FirstChild; c != nil; c = c.NextSibling {
+		switch {
+		case c.Type == html.CommentNode || isSkippedElement(c):
+			// ลบ HTML comments, <details> (spoiler boxes), <hr> ฯลฯ
+			continue
+
+		case c.Type == html.ElementNode && c.DataAtom == atom.Figure:
+			flushInline()
+			img := findElement(c, atom.Img)
+			if img == nil {
+				segments = append(segments, e.parseContentWithFigures(c)...)
+				continue
+			}
+			// <p style="text-align: ..">&nbsp;</p> ที่ตามหลัง figure ใช้กำหนด alignment ของรูป
+			following := findFollowingAlignParagraph(c)
+			if segment, ok := e.createFigureSegment(img, c, following); ok {
+				segments = append(segments, segment)
+			}
+			if following != nil {
+				c = following
+			}
+
+		case isListElement(c):
+			flushInline()
+			segments = append(segments, e.parseList(c, 0)...)
+
+		case isTableElement(c):
+			flushInline()
+			segments = append(segments, contentSegment{Type: "table", Table: e.parseTable(c)})
+
+		case isBlockElement(c) && (hasBlockChild(c) || (c.DataAtom == atom.Div && len(splitAtParagraphBreaks(childNodes(c))) > 1)):
+			// <div> ที่มีแต่ข้อความแต่คั่นด้วย <br><br> ก็แยกเป็นหลาย paragraph
+			flushInline()
+			segments = append(segments, withBlockStyle(e.parseContentWithFigures(c), c)...)
+
+		case isBlockElement(c):
+			flushInline()
+			// <p><img></p> ถือเป็นรูปภาพ
+			if img := findSoleImage(childNodes(c)); img != nil {
+				if segment, ok := e.createFigureSegment(img, c, nil); ok {
+					segments = append(segments, segment)
+				}
+				continue
+			}
+			segments = append(segments, contentSegment{
+				Type: "text",
+				Node: c,
+			})
+
+		default:
+			inline = append(inline, c)
+		}
+	}
+	flushInline()
+
+	return segments
+}
+
+// สร้าง figure segment จาก <img> พร้อม container (<figure> หรือ <p>) และ <p> ที่ตามหลัง
+func (e *Exporter) createFigureSegment(img, container, following *html.Node) (contentSegment, bool) {
+	imageURL := getAttr(img, "src")
+	if imageURL == "" {
+		return contentSegment{}, false
+	}
+
+	figcaption := ""
+	if container != nil && container.DataAtom == atom.Figure {
+		if caption := findElement(container, atom.Figcaption); caption != nil {
+			figcaption = strings.TrimSpace(textContent(caption))
+		}
+	}
+
+	// ดึง align
+	align := e.extractAlignFromImageWithContext(img, container, following)
+
+	figure := figureRef{
+		URL:            imageURL,
+		Align:          align,
+		Caption:        figcaption,
+		ContainerStyle: getAttr(container, "style"),
+		ImageStyle:     getAttr(img, "style"),
+		WidthAttr:      getAttr(img, "width"),
+	}
+	e.logf("🔍 Processing image: URL=%s, Caption=%s, Style=%q, Align=%s\n", displayURL(imageURL), figcaption, figure.ContainerStyle+figure.ImageStyle, align)
+
+	return contentSegment{
+		Type:   "figure",
+		Figure: figure,
+	}, true
+}
+
+// หา <p> ว่างที่มี text-align ซึ่งตามหลัง figure (ข้าม whitespace)
+func findFollowingAlignParagraph(figure *html.Node) *html.Node {
+	for n := figure.NextSibling; n != nil; n = n.NextSibling {
+		if n.Type == html.TextNode && strings.TrimSpace(n.Data) == "" {
+			continue
+		}
+		if n.Type != html.ElementNode || n.DataAtom != atom.P {
+			return nil
+		}
+		if _, ok := imageAlign(getAttr(n, "style"), "text-align"); !ok || !isEmptyOrOnlyNbsp(textContent(n)) {
+			return nil
+		}
+		return n
+	}
+	return nil
+}
+
+// ฟังก์ชันตรวจสอบว่าเป็น empty หรือมีแค่ &nbsp;
+func isEmptyOrOnlyNbsp(content string) bool {
+	// ลบ whitespace ธรรมดา
+	trimmed := strings.TrimSpace(content)
+
+	// ตรวจสอบว่าว่างหรือมีแค่ non-breaking spaces
+	if trimmed == "" {
+		return true
+	}
+
+	// แปลง non-breaking space เป็น regular space
+	// แล้วตรวจสอบว่าเหลือแค่ whitespace หรือไม่
+	withoutNbsp := strings.ReplaceAll(trimmed, "\u00A0", " ")
+	finalCheck := strings.TrimSpace(withoutNbsp)
+
+	return finalCheck == ""
+}
+
+// ฟังก์ชันสร้าง empty paragraph ที่รักษา attributes
+func (e *Exporter) createEmptyParagraphWithAttributes(n *html.Node) Paragraph {
+	para := Paragraph{
+		Props: e.bodyParagraphProps(n),
+		Runs: []Run{
+			{
+				Text: &Text{Value: "", Space: "preserve"},
+			},
+		},
+	}
+	e.bookmarkAnchors(&para, []*html.Node{n})
+	return para
+}
+
+// bodyParagraphProps คืน PPr ของย่อหน้าเนื้อหาจาก block element
+// <h2>-<h6>, <blockquote> และ <pre> ใช้ style ของตัวเอง (ดู blockParagraphStyles)
+// นอกนั้นใช้ BodyText แล้วแทนด้วย paragraphStyle ของ StyleRules ที่ตรงกัน (ค่าเริ่มต้น class="indent-a" ใช้ BodyTextIndent)
+// text-align, margin, padding, text-indent และ line-height ใน style attribute เป็น direct formatting
+func (e *Exporter) bodyParagraphProps(n *html.Node) *PPr {
+	props := &PPr{PStyle: &PStyle{Val: "BodyText"}}
+	if style, ok := blockParagraphStyles[n.DataAtom]; ok {
+		props.PStyle.Val = style
+	}
+
+	// em คิดจาก font-size ของ element เอง (ถ้ามี) หรือขนาดเริ่มต้นของเอกสาร
+	style := getAttr(n, "style")
+	base := e.typography().FontSize
+	fontSize := base
+	if value, ok := cssProperty(style, "font-size"); ok {
+		if size, ok := cssFontSize(value, base, base); ok {
+			fontSize = size
+		}
+	}
+	for _, rule := range e.styleRules.match(n) {
+		if rule.ParagraphStyle != "" {
+			props.PStyle.Val = rule.ParagraphStyle
+		}
+		applyParagraphCSS(props, rule.CSS, fontSize)
+	}
+	applyParagraphCSS(props, style, fontSize)
+	return props
+}
+
+func (e *Exporter) createParagraphFromHTML(n *html.Node) Paragraph {
+	para := Paragraph{
+		Props: e.bodyParagraphProps(n),
+		Runs:  []Run{},
+	}
+
+	// แปลง content เป็น runs โดยสืบทอด formatting จาก block element
+	para.Runs = e.parseContentToRuns(childNodes(n), e.baseRunStyle().inherit(n))
+	// บรรทัดว่างท้าย <pre> ไม่แสดงใน browser
+	if n.DataAtom == atom.Pre && len(para.Runs) > 0 && para.Runs[len(para.Runs)-1].Break != nil {
+		para.Runs = para.Runs[:len(para.Runs)-1]
+	}
+	e.bookmarkAnchors(&para, []*html.Node{n})
+
+	return para
+}
+
+// สร้าง paragraph จาก inline nodes ที่ไม่มี <p> ครอบ
+// parent คือ block ที่ inline nodes อยู่ข้างใน (nil = อยู่ที่ระดับบนสุดของบท)
+func (e *Exporter) createParagraphFromInline(nodes []*html.Node, parent *html.Node) Paragraph {
+	props, style := &PPr{PStyle: &PStyle{Val: "BodyText"}}, e.baseRunStyle()
+	if parent != nil {
+		props, style = e.bodyParagraphProps(parent), style.inherit(parent)
+	}
+	para := Paragraph{
+		Props: props,
+		Runs:  e.parseContentToRuns(nodes, style),
+	}
+	e.bookmarkAnchors(&para, nodes)
+	return para
+}
+
+// runStyle เก็บ formatting ที่สะสมมาจาก element แม่ทุกชั้น
+type runStyle struct {
+	Strong   bool // <b>, <strong> ใช้ character style Strong
+	Emphasis bool // <i>, <em> ใช้ character style Emphasis
+	Code     bool // <code>, <kbd>, <samp>, <tt> นอก <pre> ใช้ character style HTMLCode
+	Color    string
+	// Size คือ font-size เป็น half-point (0 = ตาม style) ส่วน BaseSize คือขนาดเริ่มต้นของเอกสาร
+	// ที่ใช้คำนวณ rem และ em ที่ยังไม่มี font-size ก่อนหน้า
+	Size     int
+	BaseSize int
+	// FontWeight และ FontStyle มาจาก CSS ("bold"/"italic" = เปิด, "normal" = ปิดที่สืบทอดมาจาก style)
+	FontWeight string
+	FontStyle  string
+	// Underline คือรูปแบบของ w:u ("" = ไม่มี, "none" = ยกเลิกขีดเส้นใต้ของ character style)
+	Underline    string
+	Strike       bool
+	DoubleStrike bool
+	VertAlign    string // "superscript" หรือ "subscript"
+	Highlight    string // สีของ <mark> (ชื่อสีของ Word)
+	Shading      string // background-color เป็น hex
+	SmallCaps    bool
+	Caps         bool
+	// Link คือลิงก์ของ <a> ที่ครอบอยู่ (ใช้ character style Hyperlink)
+	Link *Hyperlink
+	// CharacterStyle คือ characterStyle ของ StyleRules ที่ตรงกับ element แม่
+	CharacterStyle string
+	// Rules คือ StyleRules ของ export (nil = ไม่ใช้ rule)
+	Rules *styleRules
+	// Preformatted คือข้อความใน <pre> ที่เก็บ whitespace, tab และการขึ้นบรรทัดตามต้นฉบับ
+	Preformatted bool
+}
+
+// รวม formatting ของ element n เข้ากับ formatting ที่สืบทอดมา
+func (s runStyle) inherit(n *html.Node) runStyle {
+	switch n.DataAtom {
+	case atom.B, atom.Strong:
+		s.Strong, s.FontWeight = true, ""
+	case atom.I, atom.Em:
+		s.Emphasis, s.FontStyle = true, ""
+	case atom.Pre:
+		s.Preformatted = true
+	case atom.Code, atom.Kbd, atom.Samp, atom.Tt:
+		// paragraph ของ <pre> เป็น monospace อยู่แล้ว
+		s.Code = !s.Preformatted
+	case atom.U, atom.Ins:
+		s.Underline = "single"
+	case atom.S, atom.Strike, atom.Del:
+		s.Strike, s.DoubleStrike = true, false
+	case atom.Sup:
+		s.VertAlign = "superscript"
+	case atom.Sub:
+		s.VertAlign = "subscript"
+	case atom.Mark:
+		s.Highlight = "yellow"
+	}
+
+	// rule มีผลก่อน style attribute ของ element
+	for _, rule := range s.Rules.match(n) {
+		if rule.CharacterStyle != "" {
+			s.CharacterStyle = rule.CharacterStyle
+		}
+		if rule.CSS != "" {
+			s = s.inheritFont(rule.CSS).inheritTextEffects(rule.CSS)
+		}
+	}
+	if style := getAttr(n, "style"); style != "" {
+		s = s.inheritFont(style).inheritTextEffects(style)
+	}
+	return s
+}
+
+// baseRunStyle คือ runStyle เริ่มต้นของ paragraph ที่รู้ขนาดตัวอักษรของเอกสาร
+func (e *Exporter) baseRunStyle() runStyle {
+	return runStyle{BaseSize: e.typography().FontSize, Rules: &e.styleRules}
+}
+
+// inheritFont อ่าน color, font-size, font-weight และ font-style จาก inline style
+func (s runStyle) inheritFont(style string) runStyle {
+	if value, ok := cssProperty(style, "color"); ok {
+		if color, ok := cssColor(value); ok {
+			s.Color = color
+		}
+	}
+	if value, ok := cssProperty(style, "font-size"); ok {
+		base := s.BaseSize
+		if base <= 0 {
+			base = DefaultTypography().FontSize
+		}
+		parent := s.Size
+		if parent == 0 {
+			parent = base
+		}
+		if size, ok := cssFontSize(value, parent, base); ok {
+			s.Size = size
+		}
+	}
+	if value, ok := cssProperty(style, "font-weight"); ok {
+		if bold, ok := cssFontWeight(value); ok {
+			s.FontWeight = "normal"
+			if bold {
+				s.FontWeight = "bold"
+			}
+			// font-weight: normal ยกเลิกตัวหนาของ <b> ที่ครอบอยู่
+			s.Strong = s.Strong && bold
+		}
+	}
+	if value, ok := cssProperty(style, "font-style"); ok {
+		switch {
+		case value == "italic" || strings.HasPrefix(value, "oblique"):
+			s.FontStyle = "italic"
+		case value == "normal":
+			s.FontStyle, s.Emphasis = "normal", false
+		}
+	}
+	return s
+}
+
+// inheritTextEffects อ่าน text-decoration, vertical-align, background-color,
+// font-variant และ text-transform จาก inline style
+func (s runStyle) inheritTextEffects(style string) runStyle {
+	if underline, strike, double, ok := textDecoration(style); ok {
+		// text-decoration: none ยกเลิกขีดเส้นใต้และขีดฆ่าที่สืบทอดมา (รวมขีดเส้นใต้ของลิงก์)
+		s.Underline, s.Strike, s.DoubleStrike = underline, strike && !double, double
+		if underline == "" && s.Link != nil {
+			s.Underline = "none"
+		}
+	}
+	switch value, _ := cssProperty(style, "vertical-align"); value {
+	case "super":
+		s.VertAlign = "superscript"
+	case "sub":
+		s.VertAlign = "subscript"
+	case "baseline":
+		s.VertAlign = ""
+	}
+	for _, property := range []string{"background", "background-color"} {
+		value, ok := cssProperty(style, property)
+		if !ok {
+			continue
+		}
+		// สีพื้นหลังที่กำหนดเองแทนสีของ <mark>
+		if color, ok := cssColor(value); ok {
+			s.Shading, s.Highlight = color, ""
+		} else if value == "transparent" || value == "none" {
+			s.Shading, s.Highlight = "", ""
+		}
+	}
+	for _, property := range []string{"font-variant", "font-variant-caps"} {
+		if value, ok := cssProperty(style, property); ok {
+			s.SmallCaps = value == "small-caps"
+		}
+	}
+	if value, ok := cssProperty(style, "text-transform"); ok {
+		s.Caps = value == "uppercase"
+	}
+	return s
+}
+
+// แปลง runStyle เป็น RPr (nil ถ้าไม่มี formatting)
+// ตัวหนาและตัวเอียงจาก tag ใช้ character style ส่วน CSS ใน style attribute เป็น direct formatting
+func (s runStyle) rPr() *RPr {
+	// run หนึ่งมี rStyle ได้ค่าเดียว ตามลำดับ Hyperlink, characterStyle ของ rule, HTMLCode, Strong, Emphasis
+	// รูปแบบที่ซ้อนอยู่ใต้ style อื่นจึงเป็น direct formatting
+	rPr := &RPr{}
+	if s.Link != nil {
+		rPr.RStyle = &RStyle{Val: "Hyperlink"}
+	}
+	if s.CharacterStyle != "" && rPr.RStyle == nil {
+		rPr.RStyle = &RStyle{Val: s.CharacterStyle}
+	}
+	if s.Code {
+		if rPr.RStyle == nil {
+			rPr.RStyle = &RStyle{Val: "HTMLCode"}
+		} else {
+			rPr.Fonts = &RFonts{Ascii: monospaceFont, HAnsi: monospaceFont}
+		}
+	}
+	if s.Strong {
+		if rPr.RStyle == nil {
+			rPr.RStyle = &RStyle{Val: "Strong"}
+		} else {
+			rPr.Bold = &Bold{}
+		}
+	}
+	if s.Emphasis {
+		if rPr.RStyle == nil {
+			rPr.RStyle = &RStyle{Val: "Emphasis"}
+		} else {
+			rPr.Italic = &Italic{}
+		}
+	}
+	// ตัวหนาและตัวเอียงจาก CSS เป็น direct formatting เสมอ
+	switch s.FontWeight {
+	case "bold":
+		rPr.Bold = &Bold{}
+	case "normal":
+		rPr.Bold = &Bold{Val: "0"}
+	}
+	switch s.FontStyle {
+	case "italic":
+		rPr.Italic = &Italic{}
+	case "normal":
+		rPr.Italic = &Italic{Val: "0"}
+	}
+	if s.Caps {
+		rPr.Caps = &Caps{}
+	}
+	if s.SmallCaps {
+		rPr.SmallCaps = &SmallCaps{}
+	}
+	if s.DoubleStrike {
+		rPr.DStrike = &DStrike{}
+	} else if s.Strike {
+		rPr.Strike = &Strike{}
+	}
+	if s.Color != "" {
+		rPr.Color = &Color{Val: s.Color}
+	}
+	if s.Size > 0 {
+		rPr.Size = &Size{Val: strconv.Itoa(s.Size)}
+	}
+	if s.Highlight != "" {
+		rPr.Highlight = &Highlight{Val: s.Highlight}
+	}
+	if s.Underline != "" {
+		rPr.Underline = &Underline{Val: s.Underline}
+	}
+	if s.Shading != "" {
+		rPr.Shd = &Shd{Val: "clear", Color: "auto", Fill: s.Shading}
+	}
+	if s.VertAlign != "" {
+		rPr.VertAlign = &VertAlign{Val: s.VertAlign}
+	}
+	if *rPr == (RPr{}) {
+		return nil
+	}
+	return rPr.mirrorComplexScript()
+}
+
+// แปลง inline nodes เป็น runs โดยเดิน DOM tree แบบ recursive ตามลำดับใน source
+func (e *Exporter) parseContentToRuns(nodes []*html.Node, style runStyle) []Run {
+	var runs []Run
+	for _, n := range nodes {
+		runs = e.appendRunsFromNode(runs, n, style)
+	}
+	return runs
+}
+
+func (e *Exporter) appendRunsFromNode(runs []Run, n *html.Node, style runStyle) []Run {
+	switch n.Type {
+	case html.TextNode:
+		// whitespace ใน HTML ไม่ใช่ line break (ยกเว้นใน <pre>)
+		text := htmlWhitespaceReplacer.Replace(n.Data)
+		if style.Preformatted {
+			text = preformattedNewlineReplacer.Replace(n.Data)
+		}
+		if text == "" {
+			return runs
+		}
+		return append(runs, style.linkRuns(e.processLineBreaksInText(text, style.rPr()))...)
+
+	case html.ElementNode:
+		if isSkippedElement(n) {
+			return runs
+		}
+		switch n.DataAtom {
+		case atom.Br:
+			return append(runs, style.linkRuns(e.processLineBreaksInText("\n", style.rPr()))...)
+		case atom.Img:
+			// รูปภาพที่อยู่กลางข้อความไม่รองรับ
+			return runs
+		case atom.A:
+			// <a> ที่ไม่มี href หรือ href ที่ไม่รองรับเก็บแค่ข้อความ
+			if link := e.hyperlink(getAttr(n, "href")); link != nil {
+				style.Link = link
+			}
+		}
+
+		style = style.inherit(n)
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			runs = e.appendRunsFromNode(runs, c, style)
+		}
+	}
+	return runs
+}
+
+// linkRuns ใส่ลิงก์ของ style ให้ runs ที่สร้างจากข้อความภายใน <a>
+func (s runStyle) linkRuns(runs []Run) []Run {
+	if s.Link != nil {
+		for i := range runs {
+			runs[i].Hyperlink = s.Link
+		}
+	}
+	return runs
+}
+
+var (
+	htmlWhitespaceReplacer      = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ")
+	preformattedNewlineReplacer = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+)
+
+// ฟังก์ชันประมวลผล line breaks และ tab ใน text
+// ถ้าเปิด ThaiWordBreaks จะใส่ zero-width space ระหว่างคำไทยด้วย
+func (e *Exporter) processLineBreaksInText(text string, props *RPr) []Run {
+	var runs []Run
+
+	// แยก text ตาม line break
+	parts := strings.Split(text, "\n")
+
+	for i, part := range parts {
+		// เพิ่ม text run (tab ซึ่งมีได้เฉพาะใน <pre> เป็น w:tab)
+		if part != "" || len(parts) == 1 {
+			pieces := strings.Split(part, "\t")
+			for j, piece := range pieces {
+				if j > 0 {
+					runs = append(runs, Run{Props: props, Tab: &Tab{}})
+				}
+				if piece == "" && len(pieces) > 1 {
+					continue
+				}
+				if e.segmenter != nil {
+					piece = e.segmenter.InsertBreaks(piece)
+				}
+				run := Run{
+					Props: props,
+					Text:  &Text{Value: piece, Space: "preserve"},
+				}
+				runs = append(runs, run)
+			}
+		}
+
+		// เพิ่ม line break run (ยกเว้น part สุดท้าย)
+		if i < len(parts)-1 {
+			breakRun := Run{
+				Props: props,
+				Break: &Break{}, // line break (ไม่ใส่ Type สำหรับ line break ธรรมดา)
+			}
+			runs = append(runs, breakRun)
+		}
+	}
+
+	return runs
+}
+
+// element ที่ไม่ต้องแปลงเป็นเนื้อหา
+var skippedElements = map[atom.Atom]bool{
+	atom.Details:  true, // spoiler boxes
+	atom.Hr:       true,
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Template: true,
+	atom.Noscript: true,
+}
+
+// element ระดับ block ที่แยก paragraph ออกจากกัน
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true,
+	atom.Main: true, atom.Header: true, atom.Footer: true, atom.Aside: true,
+	atom.Nav: true, atom.Blockquote: true, atom.Center: true, atom.Address: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Ul: true, atom.Ol: true, atom.Li: true, atom.Dl: true, atom.Dt: true, atom.Dd: true,
+	atom.Pre: true, atom.Table: true, atom.Thead: true, atom.Tbody: true, atom.Tfoot: true,
+	atom.Tr: true, atom.Td: true, atom.Th: true, atom.Caption: true, atom.Figure: true,
+}
+
+func isSkippedElement(n *html.Node) bool {
+	return n.Type == html.ElementNode && skippedElements[n.DataAtom]
+}
+
+func isBlockElement(n *html.Node) bool {
+	return n.Type == html.ElementNode && blockElements[n.DataAtom]
+}
+
+// ตรวจสอบว่ามี block element อยู่ภายใน (ต้องแยกเป็นหลาย paragraph)
+func hasBlockChild(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if isBlockElement(c) {
+			return true
+		}
+	}
+	return false
+}
+
+func childNodes(n *html.Node) []*html.Node {
+	var nodes []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		nodes = append(nodes, c)
+	}
+	return nodes
+}
+
+func getAttr(n *html.Node, key string) string {
+	if n == nil {
+		return ""
+	}
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func hasClass(n *html.Node, class string) bool {
+	for _, c := range strings.Fields(getAttr(n, "class")) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// ดึงข้อความทั้งหมดภายใน node
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	if isSkippedElement(n) {
+		return ""
+	}
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(textContent(c))
+	}
+	return sb.String()
+}
+
+func textContentOf(nodes []*html.Node) string {
+	var sb strings.Builder
+	for _, n := range nodes {
+		sb.WriteString(textContent(n))
+	}
+	return sb.String()
+}
+
+// หา element แรกที่ตรงกับ atom (depth-first)
+func findElement(n *html.Node, a atom.Atom) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.DataAtom == a {
+			return c
+		}
+		if found := findElement(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func containsElement(nodes []*html.Node, a atom.Atom) bool {
+	for _, n := range nodes {
+		if n.Type == html.ElementNode && n.DataAtom == a {
+			return true
+		}
+		if findElement(n, a) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// หา <img> ถ้าใน nodes มีแค่รูปเดียวและไม่มีข้อความอื่น
+func findSoleImage(nodes []*html.Node) *html.Node {
+	var img *html.Node
+	var walk func(n *html.Node) bool
+	walk = func(n *html.Node) bool {
+		switch n.Type {
+		case html.TextNode:
+			return isEmptyOrOnlyNbsp(n.Data)
+		case html.ElementNode:
+			if n.DataAtom == atom.Img {
+				if img != nil {
+					return false
+				}
+				img = n
+				return true
+			}
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				if !walk(c) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	for _, n := range nodes {
+		if !walk(n) {
+			return nil
+		}
+	}
+	return img
+}
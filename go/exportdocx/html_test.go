@@ -0,0 +1,216 @@
+package exportdocx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"go-export-docx/thaiseg"
+)
+
+// แปลง runs เป็นข้อความสั้นๆ เช่น "[b#FF0000]text" เพื่อเทียบลำดับและ formatting
+func describeRuns(runs []Run) string {
+	parts := make([]string, 0, len(runs))
+	for _, run := range runs {
+		var sb strings.Builder
+		if run.Props != nil {
+			sb.WriteString("[")
+			if run.Props.Bold != nil || (run.Props.RStyle != nil && run.Props.RStyle.Val == "Strong") {
+				sb.WriteString("b")
+			}
+			if run.Props.Italic != nil || (run.Props.RStyle != nil && run.Props.RStyle.Val == "Emphasis") {
+				sb.WriteString("i")
+			}
+			if run.Props.Color != nil {
+				sb.WriteString("#" + run.Props.Color.Val)
+			}
+			if run.Props.Size != nil {
+				sb.WriteString("@" + run.Props.Size.Val)
+			}
+			sb.WriteString("]")
+		}
+		switch {
+		case run.Break != nil:
+			sb.WriteString("<br>")
+		case run.Text != nil:
+			sb.WriteString(run.Text.Value)
+		}
+		parts = append(parts, sb.String())
+	}
+	return strings.Join(parts, "|")
+}
+
+func parseFragment(t *testing.T, content string) []*html.Node {
+	t.Helper()
+	root := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(content), root)
+	if err != nil {
+		t.Fatalf("ParseFragment(%q): %v", content, err)
+	}
+	return nodes
+}
+
+func TestParseContentToRunsKeepsSourceOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "plain around bold",
+			html: "Hello <b>world</b> again",
+			want: "Hello |[b]world| again",
+		},
+		{
+			name: "plain around italic",
+			html: "Hello <em>world</em> again",
+			want: "Hello |[i]world| again",
+		},
+		{
+			name: "nested strong em",
+			html: "<strong><em>x</em></strong>",
+			want: "[bi]x",
+		},
+		{
+			name: "span with bold and italic children",
+			html: `<span><b>a</b> and <i>b</i></span>`,
+			want: "[b]a| and |[i]b",
+		},
+		{
+			name: "colored span wraps strong",
+			html: `before <span style="color: #ff0000"><strong>red</strong></span> after`,
+			want: "before |[b#FF0000]red| after",
+		},
+		{
+			name: "colored span wraps italic and plain text",
+			html: `<span style="color: #000000">one <i>two</i> three</span>`,
+			want: "[#000000]one |[i#000000]two|[#000000] three",
+		},
+		{
+			name: "several emphasis spans in one sentence",
+			html: `<i>first</i> plain <b>second</b> plain <span style="color:#00ff00">third</span>`,
+			want: "[i]first| plain |[b]second| plain |[#00FF00]third",
+		},
+		{
+			name: "font size inherited",
+			html: `<span style="font-size: 14pt">big <b>bold</b></span>`,
+			want: "[@28]big |[b@28]bold",
+		},
+		{
+			name: "line breaks keep position",
+			html: "a<br>b<br/>c",
+			want: "a|<br>|b|<br>|c",
+		},
+		{
+			name: "bold line break",
+			html: "<b>a<br>b</b>",
+			want: "[b]a|[b]<br>|[b]b",
+		},
+		{
+			name: "entities decoded once",
+			html: "Tom &amp; Jerry&nbsp;!",
+			want: "Tom & Jerry !",
+		},
+		{
+			name: "source newlines are spaces",
+			html: "one\ntwo",
+			want: "one two",
+		},
+		{
+			name: "details and comments dropped",
+			html: "a<!-- note --><details>spoiler</details>b",
+			want: "a|b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := New(Options{}).parseContentToRuns(parseFragment(t, tt.html), runStyle{})
+			if got := describeRuns(runs); got != tt.want {
+				t.Errorf("parseContentToRuns(%q)\n got: %s\nwant: %s", tt.html, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertHTMLToParagraphsChapters(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "chapter_*.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no chapter fixtures in testdata")
+	}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".html")
+		t.Run(name, func(t *testing.T) {
+			source, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden, err := os.ReadFile(strings.TrimSuffix(file, ".html") + ".golden")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, item := range convertChapterHTML(string(source)) {
+				para, ok := item.(Paragraph)
+				if !ok {
+					t.Fatalf("unexpected body item %T", item)
+				}
+				got = append(got, describeRuns(para.Runs))
+			}
+
+			want := strings.Split(strings.TrimRight(string(golden), "\n"), "\n")
+			if len(got) != len(want) {
+				t.Fatalf("got %d paragraphs, want %d\n got: %q", len(got), len(want), got)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("paragraph %d\n got: %s\nwant: %s", i+1, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+// convertChapterHTML แปลง HTML ของบทเป็น body items ผ่าน parseChapterHTML และ segmentsToParagraphs แบบเดียวกับ Export
+func convertChapterHTML(htmlContent string) []interface{} {
+	e := New(Options{})
+	return e.segmentsToParagraphs(context.Background(), e.parseChapterHTML(htmlContent))
+}
+
+func TestConvertHTMLDecodesEntitiesOnce(t *testing.T) {
+	// entity ที่ escape ไว้ต้องเป็นข้อความ ไม่ใช่ markup
+	items := convertChapterHTML(`<p>Use &lt;b&gt;bold&lt;/b&gt; tags &amp;amp; more</p><p>Tom &amp; Jerry&nbsp;!</p>`)
+	var got []string
+	for _, item := range items {
+		got = append(got, describeRuns(item.(Paragraph).Runs))
+	}
+	want := []string{"Use <b>bold</b> tags &amp; more", "Tom & Jerry\u00a0!"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("paragraphs\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestThaiWordBreaks(t *testing.T) {
+	nodes := parseFragment(t, "ภาษาไทย<b>ไม่เว้นวรรค</b><br>hello")
+
+	plain := describeRuns(New(Options{}).parseContentToRuns(nodes, runStyle{}))
+	if strings.Contains(plain, thaiseg.ZeroWidthSpace) {
+		t.Errorf("word breaks inserted without ThaiWordBreaks: %q", plain)
+	}
+
+	got := describeRuns(New(Options{ThaiWordBreaks: true}).parseContentToRuns(nodes, runStyle{}))
+	want := strings.ReplaceAll("ภาษา/ไทย|[b]ไม่/เว้นวรรค|<br>|hello", "/", thaiseg.ZeroWidthSpace)
+	if got != want {
+		t.Errorf("runs with ThaiWordBreaks\n got: %q\nwant: %q", got, want)
+	}
+}
@@ -0,0 +1,103 @@
+package exportdocx
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// anchorLink คือลิงก์ภายในบท (href="#id") ที่รอผูกกับ bookmark เมื่อสร้างบทเสร็จ
+type anchorLink struct {
+	ID   string
+	Link *Hyperlink
+}
+
+// schemes ของลิงก์ภายนอกที่ใส่เป็น relationship (นอกนั้นเก็บแค่ข้อความ)
+var hyperlinkSchemes = map[string]bool{"http": true, "https": true, "mailto": true, "ftp": true}
+
+// hyperlink สร้าง Hyperlink จาก href ของ <a> (nil ถ้าไม่ใช่ลิงก์ที่รองรับ)
+// ลิงก์ภายนอกใช้ relationship แบบ External (URL เดียวกันใช้ relationship ร่วมกัน)
+// ลิงก์ "#id" ชี้ไปยัง bookmark ของย่อหน้าที่มี id นั้นในบทเดียวกัน
+func (e *Exporter) hyperlink(href string) *Hyperlink {
+	href = strings.TrimSpace(href)
+	if id, ok := strings.CutPrefix(href, "#"); ok {
+		if id == "" {
+			return nil
+		}
+		link := &Hyperlink{History: "1"}
+		e.anchorLinks = append(e.anchorLinks, anchorLink{ID: id, Link: link})
+		return link
+	}
+
+	u, err := url.Parse(href)
+	if err != nil || !hyperlinkSchemes[strings.ToLower(u.Scheme)] {
+		return nil
+	}
+	relID, ok := e.hyperlinkRelIDs[href]
+	if !ok {
+		relID = e.nextRelID()
+		e.hyperlinkRelIDs[href] = relID
+		e.hyperlinkRels = append(e.hyperlinkRels, Relationship{
+			Id:         relID,
+			Type:       officeRelationships + "hyperlink",
+			Target:     href,
+			TargetMode: "External",
+		})
+	}
+	return &Hyperlink{RelId: relID, History: "1"}
+}
+
+// bookmarkAnchors ใส่ bookmark ให้ paragraph ถ้า nodes มี element ที่มี id (หรือ <a name>)
+// ทุก id ในย่อหน้าชี้ไปที่ bookmark เดียวกัน
+func (e *Exporter) bookmarkAnchors(para *Paragraph, nodes []*html.Node) {
+	var ids []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type != html.ElementNode {
+			return
+		}
+		if id := getAttr(n, "id"); id != "" {
+			ids = append(ids, id)
+		}
+		if name := getAttr(n, "name"); name != "" && n.DataAtom == atom.A {
+			ids = append(ids, name)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	for _, n := range nodes {
+		walk(n)
+	}
+	if len(ids) == 0 || para.BookmarkStart != nil {
+		return
+	}
+
+	e.anchorCounter++
+	name := fmt.Sprintf("_Anchor%d", e.anchorCounter)
+	para.BookmarkStart, para.BookmarkEnd = e.bookmark(name)
+	for _, id := range ids {
+		if _, exists := e.anchors[id]; !exists {
+			e.anchors[id] = name
+		}
+	}
+}
+
+// resolveAnchorLinks ผูกลิงก์ "#id" ของบทปัจจุบันกับ bookmark แล้วล้าง state ของบท
+// id ซ้ำกันได้ระหว่างบท (เช่น เชิงอรรถ note1 ของแต่ละบท) ลิงก์จึงชี้ภายในบทเดียวกันเท่านั้น
+// ลิงก์ที่หาเป้าหมายไม่พบไม่มีทั้ง anchor และ r:id ซึ่ง RunList เขียนเป็นข้อความธรรมดา
+func (e *Exporter) resolveAnchorLinks() {
+	for _, pending := range e.anchorLinks {
+		name, ok := e.anchors[pending.ID]
+		if !ok {
+			e.logf("⚠️ Link target #%s not found\n", pending.ID)
+			continue
+		}
+		pending.Link.Anchor = name
+	}
+	e.anchorLinks = nil
+	e.anchors = make(map[string]string)
+}
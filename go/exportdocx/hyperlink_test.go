@@ -0,0 +1,71 @@
+package exportdocx
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestExportHyperlinks(t *testing.T) {
+	chapters := []ChapterData{
+		{ID: "1", Chapter: "บทที่ 1", Body: `<p>สนับสนุนได้ที่ <a href="https://www.patreon.com/x?a=1&amp;b=2">Patreon <b>ของเรา</b></a> หรือ <a href="https://www.patreon.com/x?a=1&amp;b=2">ลิงก์เดิม</a></p>` +
+			`<p>ดู<a href="#note1">เชิงอรรถ</a> <a href="javascript:alert(1)">ไม่ใช่ลิงก์</a> <a href="#missing">หาย</a></p>` +
+			`<p id="note1">เชิงอรรถ 1</p>`},
+		{ID: "2", Chapter: "บทที่ 2", Body: `<p><a href="#note1">กลับ</a></p><p><span id="note1">เชิงอรรถบทที่ 2</span></p>`},
+	}
+
+	var buf bytes.Buffer
+	if err := Export(context.Background(), chapters, &buf, Options{}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	files := readZipFiles(t, buf.Bytes())
+	doc := compactXML(files["word/document.xml"])
+	rels := files["word/_rels/document.xml.rels"]
+
+	// URL เดียวกันใช้ relationship เดียว และ & ใน URL ถูก escape
+	if got := strings.Count(rels, `/relationships/hyperlink"`); got != 1 {
+		t.Errorf("got %d hyperlink relationships, want 1:\n%s", got, rels)
+	}
+	if !strings.Contains(rels, `Target="https://www.patreon.com/x?a=1&amp;b=2" TargetMode="External"`) {
+		t.Errorf("hyperlink relationship missing or not external:\n%s", rels)
+	}
+	relID := relationshipID(t, rels, "https://www.patreon.com/x?a=1&amp;b=2")
+
+	// run ทั้งหมดของ <a> เดียวกันอยู่ใน w:hyperlink เดียว
+	link := `<w:hyperlinkr:id="` + relID + `"w:history="1"><w:r><w:rPr><w:rStylew:val="Hyperlink"></w:rStyle></w:rPr><w:txml:space="preserve">Patreon</w:t></w:r>` +
+		`<w:r><w:rPr><w:rStylew:val="Hyperlink"></w:rStyle><w:b></w:b><w:bCs></w:bCs></w:rPr><w:txml:space="preserve">ของเรา</w:t></w:r></w:hyperlink>`
+	if !strings.Contains(doc, link) {
+		t.Errorf("document.xml missing grouped hyperlink %s", link)
+	}
+	if got := strings.Count(doc, `r:id="`+relID+`"`); got != 2 {
+		t.Errorf("got %d hyperlinks to %s, want 2", got, relID)
+	}
+
+	// ลิงก์ภายในชี้ไปที่ bookmark ของย่อหน้าที่มี id ในบทเดียวกัน
+	for _, want := range []string{
+		`<w:hyperlinkw:anchor="_Anchor1"w:history="1">`,
+		`<w:bookmarkStartw:id="1"w:name="_Anchor1"></w:bookmarkStart>`,
+		`<w:hyperlinkw:anchor="_Anchor2"w:history="1">`,
+		`<w:bookmarkStartw:id="3"w:name="_Anchor2"></w:bookmarkStart>`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("document.xml missing %s", want)
+		}
+	}
+
+	// href ที่ไม่รองรับหรือหาเป้าหมายไม่พบ เก็บข้อความไว้
+	if strings.Contains(doc, "javascript") || !strings.Contains(doc, "ไม่ใช่ลิงก์") {
+		t.Errorf("unsupported link should keep only its text")
+	}
+	if !strings.Contains(doc, `<w:r><w:txml:space="preserve">หาย</w:t></w:r>`) {
+		t.Errorf("link with missing target should be a plain run")
+	}
+	if strings.Contains(doc, `<w:hyperlinkw:history="1">`) {
+		t.Errorf("document.xml has a hyperlink without anchor or r:id")
+	}
+
+	if !strings.Contains(files["word/styles.xml"], `w:styleId="Hyperlink"`) {
+		t.Errorf("styles.xml missing Hyperlink style")
+	}
+}
@@ -0,0 +1,333 @@
+package exportdocx
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"strconv"
+	"strings"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+)
+
+// โครงสร้างสำหรับจัดการรูปภาพ
+// เพิ่ม field สำหรับ figcaption ใน ImageInfo struct
+type ImageInfo struct {
+	URL      string
+	Data     []byte
+	Filename string
+	RelId    string
+	Width    int
+	Height   int
+	Align    string // "left", "center", "right"
+	Caption  string // เพิ่มฟิลด์นี้สำหรับ figcaption
+	ID       int    // id ของ wp:docPr (ไม่ซ้ำกันในเอกสาร)
+}
+
+// นามสกุลไฟล์ของแต่ละ format ที่ Word แสดงได้ (ชื่อ format ตาม image.DecodeConfig)
+var imageExtensions = map[string]string{
+	"png":  ".png",
+	"jpeg": ".jpg",
+	"gif":  ".gif",
+	"bmp":  ".bmp",
+	"tiff": ".tiff",
+}
+
+// downloadImage โหลดรูปของ figure แล้วคำนวณขนาดที่แสดงจากขนาดจริงของรูปและ style
+func (e *Exporter) downloadImage(ctx context.Context, figure figureRef) (ImageInfo, error) {
+	// ใช้ผลจาก download stage (ถ้ายังไม่เคยโหลดจะดาวน์โหลดตอนนี้)
+	fetched := e.fetcher.fetch(ctx, figure.URL)
+	if fetched.Err != nil {
+		return ImageInfo{}, fetched.Err
+	}
+
+	imageData := fetched.Data
+
+	// ตรวจ format จาก magic bytes เพราะหลาย server ส่ง Content-Type ผิด
+	// (เช่น application/octet-stream)
+	cfg, format, err := detectImageFormat(imageData)
+	if err != nil {
+		return ImageInfo{}, err
+	}
+	e.logf("Content-Type: %s, detected: %s\n", fetched.ContentType, format)
+
+	// สร้างชื่อไฟล์จาก URL hash
+	hasher := md5.New()
+	hasher.Write([]byte(figure.URL))
+	hash := hex.EncodeToString(hasher.Sum(nil))
+
+	// กำหนดนามสกุลไฟล์ตาม format ที่ตรวจพบ
+	ext, ok := imageExtensions[format]
+	if !ok {
+		// Word แสดง format อื่น (เช่น WebP) ไม่ได้ ต้องแปลงเป็น JPEG/PNG ก่อนฝัง
+		converted, convertedExt, err := convertImage(imageData, format)
+		if err != nil {
+			return ImageInfo{}, err
+		}
+		e.logf("🔁 Converted %s to %s (%d -> %d bytes)\n", format, convertedExt, len(imageData), len(converted))
+		imageData, ext = converted, convertedExt
+	}
+
+	filename := fmt.Sprintf("image%d_%s%s", e.imageCounter, hash[:8], ext)
+
+	// ขนาดจริงของรูป (px)
+	realWidth, realHeight := cfg.Width, cfg.Height
+
+	// คำนวณขนาดที่แสดง ไม่เกินความกว้างของพื้นที่ข้อความในหน้า (หรือของเซลล์ในตาราง)
+	width, height := computeImageSize(figure, realWidth, realHeight, e.contentWidthPx(), e.typography().FontSize)
+	e.logf("📐 Image size: %dx%d px -> %dx%d px\n", realWidth, realHeight, width, height)
+
+	imageInfo := ImageInfo{
+		URL:      figure.URL,
+		Data:     imageData,
+		Filename: filename,
+		Width:    width,
+		Height:   height,
+		Align:    figure.Align,
+		Caption:  figure.Caption,
+	}
+
+	return e.registerImage(imageInfo), nil
+}
+
+// ลงทะเบียนรูปในเอกสาร URL เดียวกันใช้ไฟล์และ relationship ร่วมกัน
+// แต่แต่ละรูปที่แสดงจะได้ docPr id ของตัวเอง
+func (e *Exporter) registerImage(imageInfo ImageInfo) ImageInfo {
+	e.drawingCounter++
+	imageInfo.ID = e.drawingCounter
+
+	for _, existing := range e.images {
+		if existing.URL == imageInfo.URL {
+			imageInfo.Filename = existing.Filename
+			imageInfo.RelId = existing.RelId
+			return imageInfo
+		}
+	}
+
+	// สร้าง relationship ID
+	imageInfo.RelId = e.nextRelID()
+
+	e.images = append(e.images, imageInfo)
+	e.imageCounter++
+
+	return imageInfo
+}
+
+// ปรับปรุงฟังก์ชัน createImageParagraph เพื่อรวม caption
+func (e *Exporter) createImageParagraph(imageInfo ImageInfo) []interface{} {
+	var paragraphs []interface{}
+
+	// คำนวณขนาดใน EMU
+	widthEMU := imageInfo.Width * 9525
+	heightEMU := imageInfo.Height * 9525
+
+	drawing := &Drawing{
+		Inline: &Inline{
+			DistT: "0",
+			DistB: "0",
+			DistL: "0",
+			DistR: "0",
+			Extent: Extent{
+				Cx: strconv.Itoa(widthEMU),
+				Cy: strconv.Itoa(heightEMU),
+			},
+			EffectExt: EffectExt{
+				L: "0",
+				T: "0",
+				R: "0",
+				B: "0",
+			},
+			DocPr: DocPr{
+				Id:   strconv.Itoa(imageInfo.ID),
+				Name: imageInfo.Filename,
+			},
+			CNvGraphicFramePr: CNvGraphicFramePr{
+				GraphicFrameLocks: GraphicFrameLocks{
+					NoChangeAspect: "1",
+				},
+			},
+			Graphic: Graphic{
+				GraphicData: GraphicData{
+					Uri: "http://schemas.openxmlformats.org/drawingml/2006/picture",
+					Pic: Pic{
+						NvPicPr: NvPicPr{
+							CNvPr: CNvPr{
+								Id:   "0",
+								Name: imageInfo.Filename,
+							},
+							CNvPicPr: CNvPicPr{},
+						},
+						BlipFill: BlipFill{
+							Blip: Blip{
+								Embed: imageInfo.RelId,
+							},
+							Stretch: Stretch{
+								FillRect: FillRect{},
+							},
+						},
+						SpPr: SpPr{
+							Xfrm: Xfrm{
+								Off: Off{X: "0", Y: "0"},
+								Ext: Ext{
+									Cx: strconv.Itoa(widthEMU),
+									Cy: strconv.Itoa(heightEMU),
+								},
+							},
+							PrstGeom: PrstGeom{
+								Prst:  "rect",
+								AvLst: AvLst{},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// กำหนด alignment
+	var alignment *Jc
+	switch strings.ToLower(imageInfo.Align) {
+	case "left":
+		alignment = &Jc{Val: "left"}
+	case "right":
+		alignment = &Jc{Val: "right"}
+	case "center":
+		alignment = &Jc{Val: "center"}
+	default:
+		alignment = &Jc{Val: "center"}
+	}
+
+	// สร้าง image paragraph
+	imagePara := Paragraph{
+		Props: &PPr{
+			Jc:      alignment,
+			Spacing: &Spacing{After: "120"}, // ลดระยะห่างเพื่อให้ติดกับ caption
+		},
+		Runs: []Run{{
+			Drawing: drawing,
+		}},
+	}
+
+	paragraphs = append(paragraphs, imagePara)
+
+	// เพิ่ม caption paragraph (ถ้ามี)
+	if strings.TrimSpace(imageInfo.Caption) != "" {
+		captionPara := Paragraph{
+			Props: &PPr{
+				PStyle: &PStyle{Val: "Caption"}, // ตัวเอียงขนาดเล็กตาม style
+				Jc:     alignment,               // ใช้ alignment เดียวกับรูป
+			},
+			Runs: []Run{{
+				Text: &Text{
+					Value: imageInfo.Caption,
+					Space: "preserve",
+				},
+			}},
+		}
+		paragraphs = append(paragraphs, captionPara)
+	}
+
+	return paragraphs
+}
+
+// computeImageSize คำนวณขนาดรูปที่แสดง (px) โดยรักษา aspect ratio ของรูปจริง
+// width ของ figure/p เป็นกรอบของรูป (รูปขยายเต็มกรอบ) ส่วน width ของ img คิด % จากกรอบนั้น
+// และรูปจะไม่กว้างเกิน pageWidth (em คิดจาก fontSize เป็น half-point)
+func computeImageSize(figure figureRef, realWidth, realHeight, pageWidth, fontSize int) (width, height int) {
+	box := pageWidth
+	fillBox := false
+	if w, ok := cssLength(figure.ContainerStyle, "width", pageWidth, fontSize); ok {
+		box = min(w, pageWidth)
+		fillBox = true
+	}
+	if mw, ok := cssLength(figure.ContainerStyle, "max-width", pageWidth, fontSize); ok && box > mw {
+		box = mw
+	}
+
+	width = realWidth
+	if fillBox {
+		width = box
+	}
+	// width="300" attribute ของ img
+	if w, err := strconv.Atoi(strings.TrimSuffix(figure.WidthAttr, "px")); err == nil && w > 0 {
+		width = w
+	}
+	width, height = parseImageSizeFromStyle(figure.ImageStyle, width, realWidth, realHeight, box, fontSize)
+	return width, height
+}
+
+// parseImageSizeFromStyle ปรับความกว้างตาม width / max-width ใน style ของ img
+// (% คิดจาก maxWidth) แล้วคำนวณความสูงตาม aspect ratio ของรูปจริง
+func parseImageSizeFromStyle(style string, width, realWidth, realHeight, maxWidth, fontSize int) (int, int) {
+	// width: 300px, width: 50%
+	if w, ok := cssLength(style, "width", maxWidth, fontSize); ok {
+		width = w
+	}
+	// max-width: 400px, max-width: 100%
+	if mw, ok := cssLength(style, "max-width", maxWidth, fontSize); ok && width > mw {
+		width = mw
+	}
+	if width > maxWidth {
+		width = maxWidth
+	}
+	if width < 1 {
+		width = 1
+	}
+
+	// รักษา aspect ratio
+	height := realHeight
+	if realWidth > 0 {
+		height = int(float64(width)*float64(realHeight)/float64(realWidth) + 0.5)
+	}
+	if height < 1 {
+		height = 1
+	}
+	return width, height
+}
+
+// detectImageFormat ตรวจ format และขนาดจริงของรูปจาก bytes
+// ใช้ http.DetectContentType จาก magic bytes ก่อน แล้วยืนยันด้วย image.DecodeConfig
+// ข้อมูลที่ decode ไม่ได้ถือว่าไม่ใช่รูปภาพ
+func detectImageFormat(data []byte) (image.Config, string, error) {
+	sniffed := http.DetectContentType(data)
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return image.Config{}, "", fmt.Errorf("not an image (detected %s): %w", sniffed, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return image.Config{}, "", fmt.Errorf("invalid %s image size %dx%d", format, cfg.Width, cfg.Height)
+	}
+	return cfg, format, nil
+}
+
+// convertImage แปลงรูปที่ Word แสดงไม่ได้ (เช่น WebP) เป็น JPEG (รูปทึบ) หรือ PNG (รูปที่มี alpha)
+// คืนข้อมูลใหม่พร้อมนามสกุลที่ตรงกับ bytes ที่เขียน (format คือผลของ detectImageFormat ใช้ใน error)
+func convertImage(data []byte, format string) ([]byte, string, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("decode %s image (detected %s): %w", format, http.DetectContentType(data), err)
+	}
+
+	var buf bytes.Buffer
+	ext := ".png"
+	if opaque, ok := img.(interface{ Opaque() bool }); ok && opaque.Opaque() {
+		ext = ".jpg"
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("encode %s: %w", ext, err)
+	}
+	return buf.Bytes(), ext, nil
+}
@@ -0,0 +1,186 @@
+package exportdocx
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestComputeImageSize(t *testing.T) {
+	const page = 600
+	tests := []struct {
+		name         string
+		figure       figureRef
+		realW, realH int
+		wantW, wantH int
+	}{
+		{"natural size kept", figureRef{}, 100, 300, 100, 300},
+		{"wide image capped to page", figureRef{}, 2000, 500, 600, 150},
+		{"figure percent fills box", figureRef{ContainerStyle: "width:50%"}, 100, 300, 300, 900},
+		{"figure px width", figureRef{ContainerStyle: "width: 240px"}, 1200, 800, 240, 160},
+		{"figure wider than page", figureRef{ContainerStyle: "width:900px"}, 400, 400, 600, 600},
+		{"img px width", figureRef{ImageStyle: "width: 200px"}, 400, 100, 200, 50},
+		{"img percent of page", figureRef{ImageStyle: "width:25%"}, 400, 200, 150, 75},
+		{"img percent of figure", figureRef{ContainerStyle: "width:50%", ImageStyle: "width:50%"}, 400, 200, 150, 75},
+		{"img max-width", figureRef{ImageStyle: "max-width: 120px"}, 480, 360, 120, 90},
+		{"img max-width percent", figureRef{ImageStyle: "max-width:100%"}, 1600, 900, 600, 338},
+		{"width auto uses natural size", figureRef{ImageStyle: "width:auto"}, 320, 240, 320, 240},
+		{"width attribute", figureRef{WidthAttr: "160"}, 320, 480, 160, 240},
+		{"style overrides attribute", figureRef{WidthAttr: "160", ImageStyle: "width:80px"}, 320, 480, 80, 120},
+		{"max-width property is not width", figureRef{ImageStyle: "max-width:50%"}, 100, 100, 100, 100},
+		{"pt width", figureRef{ImageStyle: "width: 72pt"}, 200, 100, 96, 48},
+		{"cm width", figureRef{ImageStyle: "width: 2.54cm"}, 200, 100, 96, 48},
+		{"em width of 11pt text", figureRef{ContainerStyle: "width: 20em"}, 100, 100, 293, 293},
+		{"last declaration wins", figureRef{ImageStyle: "width: 50px; width: 100px"}, 400, 400, 100, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, h := computeImageSize(tt.figure, tt.realW, tt.realH, page, 22)
+			if w != tt.wantW || h != tt.wantH {
+				t.Errorf("computeImageSize(%+v, %d, %d) = %dx%d, want %dx%d", tt.figure, tt.realW, tt.realH, w, h, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func TestTextWidthFromSectPr(t *testing.T) {
+	// A4 (11906 twips) ลบขอบซ้ายขวา 1440 twips
+	if got := New(Options{}).textWidthPx(); got != 601 {
+		t.Errorf("textWidthPx() = %d, want 601", got)
+	}
+}
+
+func TestConvertImageFromWebP(t *testing.T) {
+	tests := []struct {
+		file    string
+		wantExt string
+	}{
+		{"testdata/opaque.lossy.webp", ".jpg"},
+		{"testdata/alpha.lossy.webp", ".png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			original, _, err := image.DecodeConfig(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("DecodeConfig(webp): %v", err)
+			}
+
+			converted, ext, err := convertImage(data, "webp")
+			if err != nil {
+				t.Fatalf("convertImage: %v", err)
+			}
+			if ext != tt.wantExt {
+				t.Errorf("ext = %s, want %s", ext, tt.wantExt)
+			}
+			cfg, format, err := image.DecodeConfig(bytes.NewReader(converted))
+			if err != nil {
+				t.Fatalf("converted bytes do not decode: %v", err)
+			}
+			if "."+format != ext && !(format == "jpeg" && ext == ".jpg") {
+				t.Errorf("converted format %s does not match ext %s", format, ext)
+			}
+			if cfg.Width != original.Width || cfg.Height != original.Height {
+				t.Errorf("converted size %dx%d, want %dx%d", cfg.Width, cfg.Height, original.Width, original.Height)
+			}
+		})
+	}
+}
+
+func TestConvertImageErrorNamesDetectedType(t *testing.T) {
+	data, err := os.ReadFile("testdata/opaque.lossy.webp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// header ครบแต่ข้อมูลรูปขาด: DecodeConfig ผ่านแต่ Decode ไม่ผ่าน
+	truncated := data[:len(data)/2]
+	if _, format, err := detectImageFormat(truncated); err != nil || format != "webp" {
+		t.Fatalf("detectImageFormat(truncated) = %s, %v", format, err)
+	}
+	_, _, err = convertImage(truncated, "webp")
+	if err == nil || !strings.Contains(err.Error(), "decode webp image (detected image/webp)") {
+		t.Errorf("convertImage error = %v, want format and sniffed type", err)
+	}
+}
+
+func TestExportEmbedsWebPAsJPEG(t *testing.T) {
+	data, err := os.ReadFile("testdata/opaque.lossy.webp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/webp")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	chapters := []ChapterData{{ID: "1", Chapter: "1", Body: `<p><img src="` + server.URL + `/a.webp"></p>`}}
+	var buf bytes.Buffer
+	if err := Export(context.Background(), chapters, &buf, Options{}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	for name, content := range readZipFiles(t, buf.Bytes()) {
+		if !strings.HasPrefix(name, "word/media/") {
+			continue
+		}
+		if !strings.HasSuffix(name, ".jpg") {
+			t.Errorf("media %s should have .jpg extension", name)
+		}
+		if _, format, err := image.DecodeConfig(strings.NewReader(content)); err != nil || format != "jpeg" {
+			t.Errorf("media %s is %q (%v), want jpeg", name, format, err)
+		}
+		return
+	}
+	t.Fatal("no media part written")
+}
+
+func TestDetectImageFormat(t *testing.T) {
+	cfg, format, err := detectImageFormat(pngBytes(t, 40, 30))
+	if err != nil || format != "png" || cfg.Width != 40 || cfg.Height != 30 {
+		t.Errorf("png: got %s %dx%d (%v)", format, cfg.Width, cfg.Height, err)
+	}
+
+	if _, _, err := detectImageFormat([]byte("<html>not found</html>")); err == nil {
+		t.Error("html payload should be rejected")
+	}
+}
+
+func TestExportSniffsImageFormat(t *testing.T) {
+	data := pngBytes(t, 40, 30)
+	for _, contentType := range []string{"application/octet-stream", "binary/octet-stream", "image/jpeg"} {
+		t.Run(contentType, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", contentType)
+				w.Write(data)
+			}))
+			defer server.Close()
+
+			chapters := []ChapterData{{ID: "1", Chapter: "1", Body: `<p><img src="` + server.URL + `/a"></p>`}}
+			var buf bytes.Buffer
+			if err := Export(context.Background(), chapters, &buf, Options{}); err != nil {
+				t.Fatalf("Export: %v", err)
+			}
+
+			var media []string
+			for name := range readZipFiles(t, buf.Bytes()) {
+				if strings.HasPrefix(name, "word/media/") {
+					media = append(media, name)
+				}
+			}
+			if len(media) != 1 || !strings.HasSuffix(media[0], ".png") {
+				t.Errorf("media = %v, want one .png", media)
+			}
+		})
+	}
+}
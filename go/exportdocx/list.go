@@ -0,0 +1,326 @@
+package exportdocx
+
+import (
+	"archive/zip"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// listNumbering คือ list หนึ่ง (<ul> หรือ <ol>) ซึ่งเป็น w:num หนึ่งตัวใน numbering.xml
+// แต่ละ <ol> มี w:num ของตัวเองเพื่อให้เริ่มนับใหม่ทุก list
+type listNumbering struct {
+	Index   int // ลำดับของ list ในเอกสาร (numId = numberingOffsets.NumID + Index + 1)
+	Ordered bool
+	Level   int    // ilvl ของรายการใน list นี้
+	Start   int    // เลขเริ่มต้นของ <ol start>
+	Format  string // numFmt จาก <ol type> ("" = ตามระดับ)
+}
+
+// listItem คือข้อมูล list ของ paragraph ที่มาจาก <li>
+type listItem struct {
+	List *listNumbering
+	// Continued คือ paragraph ที่สองเป็นต้นไปของ <li> เดียวกัน (เยื้องตามรายการแต่ไม่มีเลขหรือ bullet)
+	Continued bool
+}
+
+// ระดับลึกสุดของ list ใน Word (ilvl 0-8)
+const maxListLevel = 8
+
+// listIndent คือระยะเยื้องซ้ายของรายการระดับ ilvl (twips)
+func listIndent(ilvl int) int {
+	return 720 * (ilvl + 1)
+}
+
+var (
+	bulletChars    = []string{"•", "◦", "▪"}
+	orderedFormats = []string{"decimal", "lowerLetter", "lowerRoman"}
+)
+
+// type ของ <ol> -> numFmt
+var olTypeFormats = map[string]string{
+	"1": "decimal",
+	"a": "lowerLetter",
+	"A": "upperLetter",
+	"i": "lowerRoman",
+	"I": "upperRoman",
+}
+
+// newList ลงทะเบียน list ใหม่จาก <ul> หรือ <ol>
+func (e *Exporter) newList(n *html.Node, level int) *listNumbering {
+	list := &listNumbering{
+		Index:   len(e.lists),
+		Ordered: n.DataAtom == atom.Ol,
+		Level:   min(level, maxListLevel),
+		Start:   1,
+	}
+	if list.Ordered {
+		if start, err := strconv.Atoi(strings.TrimSpace(getAttr(n, "start"))); err == nil && start >= 0 {
+			list.Start = start
+		}
+		list.Format = olTypeFormats[strings.TrimSpace(getAttr(n, "type"))]
+	}
+	e.lists = append(e.lists, list)
+	return list
+}
+
+func isListElement(n *html.Node) bool {
+	return n.Type == html.ElementNode && (n.DataAtom == atom.Ul || n.DataAtom == atom.Ol)
+}
+
+// parseList แยก <ul>/<ol> เป็น segments โดย <li> แต่ละตัวเป็น paragraph ที่มี numPr
+// list ที่ซ้อนอยู่ใน <li> อยู่ระดับถัดไป
+func (e *Exporter) parseList(n *html.Node, level int) []contentSegment {
+	list := e.newList(n, level)
+
+	var segments []contentSegment
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch {
+		case isListElement(c):
+			// <ul> ที่อยู่ใน <ul> โดยตรง (HTML ไม่ถูกต้องแต่พบได้บ่อย)
+			segments = append(segments, e.parseList(c, level+1)...)
+		case c.Type == html.ElementNode && c.DataAtom == atom.Li:
+			segments = append(segments, e.parseListItem(c, list)...)
+		}
+	}
+	return segments
+}
+
+// parseListItem แยก <li> เป็น paragraph แรกที่มีเลขหรือ bullet และ paragraph ต่อเนื่อง
+func (e *Exporter) parseListItem(li *html.Node, list *listNumbering) []contentSegment {
+	// <li> ที่มีแค่ข้อความเป็น paragraph เดียวที่สืบทอด style และ class ของ <li>
+	if !hasBlockChild(li) {
+		return []contentSegment{{Type: "text", Node: li, List: &listItem{List: list}}}
+	}
+
+	var segments []contentSegment
+	var inline []*html.Node
+
+	item := func() *listItem {
+		return &listItem{List: list, Continued: len(segments) > 0}
+	}
+	flushInline := func() {
+		if len(inline) > 0 && (!isEmptyOrOnlyNbsp(textContentOf(inline)) || containsElement(inline, atom.Br)) {
+			segments = append(segments, contentSegment{Type: "inline", Inline: inline, List: item()})
+		}
+		inline = nil
+	}
+
+	for c := li.FirstChild; c != nil; c = c.NextSibling {
+		switch {
+		case c.Type == html.CommentNode || isSkippedElement(c):
+			continue
+		case isListElement(c):
+			flushInline()
+			segments = append(segments, e.parseList(c, list.Level+1)...)
+		case isTableElement(c):
+			flushInline()
+			segments = append(segments, contentSegment{Type: "table", Table: e.parseTable(c)})
+		case isBlockElement(c):
+			flushInline()
+			segments = append(segments, contentSegment{Type: "text", Node: c, List: item()})
+		default:
+			inline = append(inline, c)
+		}
+	}
+	flushInline()
+	return segments
+}
+
+// applyListItem ใส่ style ListParagraph และ numPr (หรือการเยื้องของ paragraph ต่อเนื่อง)
+func (e *Exporter) applyListItem(para *Paragraph, item *listItem) {
+	props := PPr{}
+	if para.Props != nil {
+		props = *para.Props
+	}
+	props.PStyle = &PStyle{Val: "ListParagraph"}
+	if item.Continued {
+		props.Ind = &Ind{Left: strconv.Itoa(listIndent(item.List.Level))}
+	} else {
+		props.NumPr = &NumPr{
+			Ilvl:  &Ilvl{Val: strconv.Itoa(item.List.Level)},
+			NumId: &NumId{Val: strconv.Itoa(e.numbering.NumID + item.List.Index + 1)},
+		}
+	}
+	para.Props = &props
+}
+
+var (
+	abstractNumIDRegex = regexp.MustCompile(`w:abstractNumId="(\d+)"`)
+	numIDRegex         = regexp.MustCompile(`<w:num\s[^>]*w:numId="(\d+)"`)
+	firstNumRegex      = regexp.MustCompile(`<w:num[\s>]`)
+)
+
+// numberingOffsets คือ ID แรกที่ converter ใช้ได้ใน numbering.xml
+// คำนวณครั้งเดียวตอนโหลดไฟล์ต้นแบบ ก่อน writeNumbering เติม list ของ converter ลงไป
+type numberingOffsets struct {
+	NumID       int // numId ที่มีอยู่แล้ว (list ของ converter ใช้ ID ต่อจากนั้น)
+	AbstractNum int // abstractNumId แรกที่ว่าง
+}
+
+// numberingPart คืน numbering.xml ที่คัดลอกจากไฟล์ต้นแบบ (nil ถ้าไม่มี)
+func (r *referenceDocx) numberingPart() *referencePart {
+	for i := range r.parts {
+		if r.parts[i].Name == referencePartNames["numbering"] {
+			return &r.parts[i]
+		}
+	}
+	return nil
+}
+
+func (r *referenceDocx) numberingOffsets() numberingOffsets {
+	part := r.numberingPart()
+	if part == nil {
+		return numberingOffsets{}
+	}
+	return numberingOffsets{
+		NumID:       max(maxAttrValue(numIDRegex, part.Data), 0),
+		AbstractNum: maxAttrValue(abstractNumIDRegex, part.Data) + 1,
+	}
+}
+
+// referenceNumbering คืน numbering.xml ของไฟล์ต้นแบบ (nil ถ้าไม่ใช้ไฟล์ต้นแบบหรือไม่มี numbering)
+func (e *Exporter) referenceNumbering() *referencePart {
+	if e.reference == nil {
+		return nil
+	}
+	return e.reference.numberingPart()
+}
+
+// maxAttrValue คืนค่ามากที่สุดของกลุ่มแรกใน regex (-1 ถ้าไม่พบ)
+func maxAttrValue(re *regexp.Regexp, data []byte) int {
+	result := -1
+	for _, m := range re.FindAllSubmatch(data, -1) {
+		if v, err := strconv.Atoi(string(m[1])); err == nil && v > result {
+			result = v
+		}
+	}
+	return result
+}
+
+// numberingLevel สร้าง w:lvl หนึ่งระดับ
+func numberingLevel(ilvl int, format, text string, start int) string {
+	return fmt.Sprintf(`
+        <w:lvl w:ilvl="%d">
+            <w:start w:val="%d"/>
+            <w:numFmt w:val="%s"/>
+            <w:lvlText w:val="%s"/>
+            <w:lvlJc w:val="left"/>
+            <w:pPr>
+                <w:ind w:left="%d" w:hanging="360"/>
+            </w:pPr>
+        </w:lvl>`, ilvl, start, format, text, listIndent(ilvl))
+}
+
+// ระดับของ list แบบมีเลข ตามรูปแบบเริ่มต้นของแต่ละระดับ
+func orderedLevel(ilvl int, format string, start int) string {
+	if format == "" {
+		format = orderedFormats[ilvl%len(orderedFormats)]
+	}
+	return numberingLevel(ilvl, format, fmt.Sprintf("%%%d.", ilvl+1), start)
+}
+
+// numberingDefinitions คืน w:abstractNum (bullet และตัวเลข) และ w:num ของทุก list
+func (e *Exporter) numberingDefinitions() (abstractNums, nums string) {
+	bulletID := e.numbering.AbstractNum
+	orderedID := bulletID + 1
+
+	abstractNums = fmt.Sprintf(`
+    <w:abstractNum w:abstractNumId="%d">
+        <w:multiLevelType w:val="hybridMultilevel"/>`, bulletID)
+	for ilvl := 0; ilvl <= maxListLevel; ilvl++ {
+		abstractNums += numberingLevel(ilvl, "bullet", bulletChars[ilvl%len(bulletChars)], 1)
+	}
+	abstractNums += fmt.Sprintf(`
+    </w:abstractNum>
+    <w:abstractNum w:abstractNumId="%d">
+        <w:multiLevelType w:val="hybridMultilevel"/>`, orderedID)
+	for ilvl := 0; ilvl <= maxListLevel; ilvl++ {
+		abstractNums += orderedLevel(ilvl, "", 1)
+	}
+	abstractNums += `
+    </w:abstractNum>`
+
+	for _, list := range e.lists {
+		numID := e.numbering.NumID + list.Index + 1
+		if !list.Ordered {
+			nums += fmt.Sprintf(`
+    <w:num w:numId="%d">
+        <w:abstractNumId w:val="%d"/>
+    </w:num>`, numID, bulletID)
+			continue
+		}
+		// startOverride ทำให้ <ol> แต่ละตัวเริ่มนับใหม่ และ lvl กำหนดรูปแบบตาม type
+		override := fmt.Sprintf(`
+            <w:startOverride w:val="%d"/>`, list.Start)
+		if list.Format != "" {
+			override += orderedLevel(list.Level, list.Format, list.Start)
+		}
+		nums += fmt.Sprintf(`
+    <w:num w:numId="%d">
+        <w:abstractNumId w:val="%d"/>
+        <w:lvlOverride w:ilvl="%d">%s
+        </w:lvlOverride>
+    </w:num>`, numID, orderedID, list.Level, override)
+	}
+	return abstractNums, nums
+}
+
+// ownsNumbering คืน true ถ้า converter ต้องเขียน numbering.xml เอง (มี list และไฟล์ต้นแบบไม่มี numbering)
+func (e *Exporter) ownsNumbering() bool {
+	return len(e.lists) > 0 && e.referenceNumbering() == nil
+}
+
+// writeNumbering เขียน numbering.xml หรือเติม list ลงใน numbering.xml ของไฟล์ต้นแบบ
+func (e *Exporter) writeNumbering(zipWriter *zip.Writer) error {
+	if len(e.lists) == 0 {
+		return nil
+	}
+	abstractNums, nums := e.numberingDefinitions()
+
+	if part := e.referenceNumbering(); part != nil {
+		// abstractNum ทั้งหมดต้องอยู่ก่อน num ตาม schema
+		content := string(part.Data)
+		end := strings.LastIndex(content, "</w:numbering>")
+		if end < 0 {
+			return fmt.Errorf("reference numbering.xml has no </w:numbering>")
+		}
+		content = content[:end] + nums + "\n" + content[end:]
+		pos := end
+		if loc := firstNumRegex.FindStringIndex(content); loc != nil {
+			pos = loc[0]
+		}
+		part.Data = []byte(content[:pos] + abstractNums + "\n" + content[pos:])
+		return nil
+	}
+
+	content := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` + abstractNums + nums + `
+</w:numbering>`
+	return writePart(zipWriter, "word/numbering.xml", []byte(content))
+}
+
+func (e *Exporter) numberingContentTypes() []contentTypeOverride {
+	if !e.ownsNumbering() {
+		return nil
+	}
+	return []contentTypeOverride{{
+		PartName:    "/word/numbering.xml",
+		ContentType: "application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml",
+	}}
+}
+
+func (e *Exporter) numberingRelationships() []Relationship {
+	if !e.ownsNumbering() {
+		return nil
+	}
+	return []Relationship{{
+		Id:     e.nextRelID(),
+		Type:   officeRelationships + "numbering",
+		Target: "numbering.xml",
+	}}
+}
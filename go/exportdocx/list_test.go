@@ -0,0 +1,114 @@
+package exportdocx
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestExportLists(t *testing.T) {
+	chapters := []ChapterData{{ID: "1", Chapter: "บทที่ 1", Body: `<ul>` +
+		`<li>ข้อแรก<ol start="3" type="a"><li>ข้อย่อย</li></ol></li>` +
+		`<li><p>ย่อหน้าแรก</p><p>ย่อหน้าต่อ</p></li>` +
+		`</ul><ol><li>หนึ่ง</li></ol>`}}
+
+	var buf bytes.Buffer
+	if err := Export(context.Background(), chapters, &buf, Options{}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	files := readZipFiles(t, buf.Bytes())
+	doc := compactXML(files["word/document.xml"])
+	numbering := files["word/numbering.xml"]
+
+	if numbering == "" {
+		t.Fatal("numbering.xml not written")
+	}
+	if !strings.Contains(files["[Content_Types].xml"], `PartName="/word/numbering.xml"`) {
+		t.Errorf("[Content_Types].xml missing numbering override")
+	}
+	if !strings.Contains(files["word/_rels/document.xml.rels"], `/relationships/numbering" Target="numbering.xml"`) {
+		t.Errorf("document.xml.rels missing numbering relationship")
+	}
+
+	// <ul> = numId 1, <ol> ที่ซ้อนอยู่ = numId 2 ระดับ 1, <ol> ตัวที่สอง = numId 3
+	for _, want := range []string{
+		`<w:pStylew:val="ListParagraph"></w:pStyle><w:numPr><w:ilvlw:val="0"></w:ilvl><w:numIdw:val="1"></w:numId></w:numPr>`,
+		`<w:numPr><w:ilvlw:val="1"></w:ilvl><w:numIdw:val="2"></w:numId></w:numPr>`,
+		`<w:numPr><w:ilvlw:val="0"></w:ilvl><w:numIdw:val="3"></w:numId></w:numPr>`,
+		// ย่อหน้าที่สองของ <li> เยื้องตามรายการโดยไม่มีเลข
+		`<w:pStylew:val="ListParagraph"></w:pStyle><w:indw:left="720"></w:ind></w:pPr><w:r><w:txml:space="preserve">ย่อหน้าต่อ</w:t>`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("document.xml missing %s", want)
+		}
+	}
+
+	for _, want := range []string{
+		`<w:startOverride w:val="3"/>`,
+		`<w:numFmt w:val="lowerLetter"/>`,
+		`<w:lvlText w:val="•"/>`,
+	} {
+		if !strings.Contains(numbering, want) {
+			t.Errorf("numbering.xml missing %s", want)
+		}
+	}
+	if got := strings.Count(numbering, "<w:num "); got != 3 {
+		t.Errorf("got %d w:num, want 3", got)
+	}
+	if !strings.Contains(files["word/styles.xml"], `w:styleId="ListParagraph"`) {
+		t.Errorf("styles.xml missing ListParagraph style")
+	}
+}
+
+func TestExportWithoutListsHasNoNumbering(t *testing.T) {
+	chapters := []ChapterData{{ID: "1", Chapter: "บทที่ 1", Body: "<p>เนื้อหา</p>"}}
+
+	var buf bytes.Buffer
+	if err := Export(context.Background(), chapters, &buf, Options{}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	files := readZipFiles(t, buf.Bytes())
+	if _, ok := files["word/numbering.xml"]; ok {
+		t.Errorf("numbering.xml should not be written without lists")
+	}
+	if strings.Contains(files["word/_rels/document.xml.rels"], "numbering") {
+		t.Errorf("document.xml.rels should not reference numbering")
+	}
+}
+
+func TestExportListsWithReferenceNumbering(t *testing.T) {
+	parts := houseTemplateParts()
+	parts["word/numbering.xml"] = `<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <w:abstractNum w:abstractNumId="4"><w:lvl w:ilvl="0"><w:numFmt w:val="decimal"/></w:lvl></w:abstractNum>
+  <w:num w:numId="7"><w:abstractNumId w:val="4"/></w:num>
+</w:numbering>`
+	parts["word/_rels/document.xml.rels"] = strings.Replace(parts["word/_rels/document.xml.rels"], "</Relationships>",
+		`  <Relationship Id="rId5" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering" Target="numbering.xml"/>
+</Relationships>`, 1)
+	reference := writeReferenceDocx(t, parts)
+	chapters := []ChapterData{{ID: "1", Chapter: "บทที่ 1", Body: "<ol><li>หนึ่ง</li></ol>"}}
+
+	var buf bytes.Buffer
+	if err := Export(context.Background(), chapters, &buf, Options{ReferenceDocx: reference}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	files := readZipFiles(t, buf.Bytes())
+	numbering := files["word/numbering.xml"]
+
+	// list ของ converter ใช้ ID ต่อจากของไฟล์ต้นแบบ และ abstractNum อยู่ก่อน num ทั้งหมด
+	for _, want := range []string{`<w:num w:numId="7">`, `<w:num w:numId="8">`, `w:abstractNumId="5"`, `w:abstractNumId="6"`} {
+		if !strings.Contains(numbering, want) {
+			t.Errorf("numbering.xml missing %s:\n%s", want, numbering)
+		}
+	}
+	if strings.Index(numbering, `w:abstractNumId="6"`) > strings.Index(numbering, "<w:num ") {
+		t.Errorf("abstractNum should come before num:\n%s", numbering)
+	}
+	if !strings.Contains(compactXML(files["word/document.xml"]), `<w:numIdw:val="8"></w:numId>`) {
+		t.Errorf("document.xml should use numId 8")
+	}
+	if got := strings.Count(files["word/_rels/document.xml.rels"], "/relationships/numbering"); got != 1 {
+		t.Errorf("got %d numbering relationships, want 1", got)
+	}
+}
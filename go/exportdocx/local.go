@@ -0,0 +1,150 @@
+package exportdocx
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// isRemoteImage ตรวจว่า src ต้องโหลดผ่าน HTTP หรือไม่
+// src แบบอื่น (data: URI, file:// และ path ในเครื่อง) โหลดด้วย loadLocalImage
+func isRemoteImage(src string) bool {
+	lower := strings.ToLower(src)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(src, "//")
+}
+
+// loadLocalImage อ่านรูปจาก data: URI หรือไฟล์ในเครื่อง
+// path แบบ relative อ้างอิงจาก assetsDir และอ่านได้เฉพาะไฟล์ที่อยู่ใน assetsDir
+// ("" = ไม่อ่านไฟล์ในเครื่องเลย ใช้ได้แค่ data: URI)
+func loadLocalImage(src, assetsDir string) *fetchedImage {
+	if strings.HasPrefix(strings.ToLower(src), "data:") {
+		data, contentType, err := decodeDataURI(src)
+		if err != nil {
+			return &fetchedImage{Err: err}
+		}
+		return &fetchedImage{Data: data, ContentType: contentType}
+	}
+
+	path, err := localImagePath(src, assetsDir)
+	if err != nil {
+		return &fetchedImage{Err: err}
+	}
+	// symlink ใน assetsDir ต้องไม่ชี้ออกไปนอก assetsDir
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		if root, err := filepath.EvalSymlinks(assetsDir); err == nil && !withinDir(resolved, root) {
+			return &fetchedImage{Err: fmt.Errorf("image %q is outside the assets directory", src)}
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return &fetchedImage{Err: err}
+	}
+	return &fetchedImage{Data: data}
+}
+
+// decodeDataURI แยก media type และข้อมูลจาก data:[<mediatype>][;base64],<data>
+func decodeDataURI(src string) ([]byte, string, error) {
+	header, payload, ok := strings.Cut(src[len("data:"):], ",")
+	if !ok {
+		return nil, "", fmt.Errorf("invalid data URI: missing ','")
+	}
+
+	contentType := header
+	isBase64 := false
+	if strings.HasSuffix(strings.ToLower(header), ";base64") {
+		contentType = header[:len(header)-len(";base64")]
+		isBase64 = true
+	}
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+
+	if !isBase64 {
+		data, err := url.PathUnescape(payload)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid data URI: %w", err)
+		}
+		return []byte(data), contentType, nil
+	}
+
+	// base64 ใน HTML มักมีช่องว่างหรือขึ้นบรรทัดใหม่ และบางครั้งไม่มี padding
+	payload = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+			return -1
+		}
+		return r
+	}, payload)
+	if unescaped, err := url.PathUnescape(payload); err == nil {
+		payload = unescaped
+	}
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		data, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(payload, "="))
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid base64 in data URI: %w", err)
+	}
+	return data, contentType, nil
+}
+
+// localImagePath แปลง file:// URL หรือ path ใน src เป็น path ของไฟล์
+// path ที่อยู่นอก assetsDir (เช่น /etc/passwd หรือ ../secret.png) ถูกปฏิเสธ
+func localImagePath(src, assetsDir string) (string, error) {
+	if assetsDir == "" {
+		return "", fmt.Errorf("local image %q: no assets directory set", src)
+	}
+	path := src
+	if strings.HasPrefix(strings.ToLower(src), "file:") {
+		u, err := url.Parse(src)
+		if err != nil {
+			return "", err
+		}
+		path = u.Path
+		if u.Host != "" && u.Host != "localhost" {
+			// file://images/a.png ถือเป็น path แบบ relative
+			path = u.Host + u.Path
+		}
+	} else {
+		// ตัด query และ fragment ที่ติดมากับ src (เช่น images/a.png?v=2)
+		if i := strings.IndexAny(path, "?#"); i >= 0 {
+			path = path[:i]
+		}
+		if unescaped, err := url.PathUnescape(path); err == nil {
+			path = unescaped
+		}
+	}
+	if path == "" {
+		return "", fmt.Errorf("empty image path in %q", src)
+	}
+
+	root, err := filepath.Abs(assetsDir)
+	if err != nil {
+		return "", err
+	}
+	path = filepath.FromSlash(path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	path = filepath.Clean(path)
+	if !withinDir(path, root) {
+		return "", fmt.Errorf("image %q is outside the assets directory", src)
+	}
+	return path, nil
+}
+
+// withinDir ตรวจว่า path (absolute และ clean แล้ว) อยู่ใน dir หรือไม่
+func withinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// displayURL ย่อ data: URI ให้สั้นพอสำหรับแสดงใน log
+func displayURL(src string) string {
+	if strings.HasPrefix(strings.ToLower(src), "data:") && len(src) > 48 {
+		return fmt.Sprintf("%s... (%d bytes)", src[:32], len(src))
+	}
+	return src
+}
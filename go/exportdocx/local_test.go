@@ -0,0 +1,137 @@
+package exportdocx
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDecodeDataURI(t *testing.T) {
+	png := pngBytes(t, 4, 3)
+	encoded := base64.StdEncoding.EncodeToString(png)
+
+	tests := []struct {
+		name     string
+		src      string
+		wantType string
+		want     []byte
+	}{
+		{"base64", "data:image/png;base64," + encoded, "image/png", png},
+		{"base64 with whitespace", "data:image/png;base64," + encoded[:8] + "\n  " + encoded[8:], "image/png", png},
+		{"unpadded base64", "data:image/png;base64," + strings.TrimRight(encoded, "="), "image/png", png},
+		{"parameters", "data:image/png;name=a.png;base64," + encoded, "image/png", png},
+		{"percent-encoded", "data:text/plain,a%20b", "text/plain", []byte("a b")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, contentType, err := decodeDataURI(tt.src)
+			if err != nil {
+				t.Fatalf("decodeDataURI: %v", err)
+			}
+			if contentType != tt.wantType || !bytes.Equal(data, tt.want) {
+				t.Errorf("got %q, %d bytes; want %q, %d bytes", contentType, len(data), tt.wantType, len(tt.want))
+			}
+		})
+	}
+
+	if _, _, err := decodeDataURI("data:image/png;base64"); err == nil {
+		t.Error("data URI without ',' should be rejected")
+	}
+}
+
+func TestLocalImagePath(t *testing.T) {
+	assets := filepath.FromSlash("/books/novel")
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"images/ch3.png", "/books/novel/images/ch3.png"},
+		{"./images/ch3.png?v=2", "/books/novel/images/ch3.png"},
+		{"images/%E0%B8%9A%E0%B8%97.png", "/books/novel/images/บท.png"},
+		{"/books/novel/img/a.png", "/books/novel/img/a.png"},
+		{"file:///books/novel/img/a.png", "/books/novel/img/a.png"},
+		{"file://localhost/books/novel/img/a.png", "/books/novel/img/a.png"},
+		{"file://images/a.png", "/books/novel/images/a.png"},
+		{"images/../cover.png", "/books/novel/cover.png"},
+	}
+	for _, tt := range tests {
+		got, err := localImagePath(tt.src, assets)
+		if err != nil {
+			t.Errorf("localImagePath(%q): %v", tt.src, err)
+			continue
+		}
+		if got != filepath.FromSlash(tt.want) {
+			t.Errorf("localImagePath(%q) = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestLocalImagePathOutsideAssetsDir(t *testing.T) {
+	assets := filepath.FromSlash("/books/novel")
+	for _, src := range []string{"/etc/passwd", "file:///etc/passwd", "../secret.png", "images/../../other/a.png", "/books/novel-2/a.png"} {
+		if path, err := localImagePath(src, assets); err == nil {
+			t.Errorf("localImagePath(%q) = %q, want error", src, path)
+		}
+	}
+	if path, err := localImagePath("images/a.png", ""); err == nil {
+		t.Errorf("local images without AssetsDir should be rejected, got %q", path)
+	}
+
+	// symlink ที่ชี้ออกนอก assetsDir
+	dir, outside := t.TempDir(), t.TempDir()
+	secret := filepath.Join(outside, "secret.png")
+	if err := os.WriteFile(secret, pngBytes(t, 2, 2), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(secret, filepath.Join(dir, "link.png")); err != nil {
+		t.Skipf("symlink: %v", err)
+	}
+	if result := loadLocalImage("link.png", dir); result.Err == nil {
+		t.Error("symlink out of the assets directory should be rejected")
+	}
+}
+
+func TestExportEmbedsLocalImages(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "images"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "images", "ch3.png"), pngBytes(t, 40, 30), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dataURI := "data:image/png;base64," + base64.StdEncoding.EncodeToString(pngBytes(t, 20, 10))
+
+	body := `<figure style="text-align: center"><img src="images/ch3.png" style="width: 50%"><figcaption>ภาพที่ 1</figcaption></figure>` +
+		`<p><img src="` + dataURI + `"></p>` +
+		`<p><img src="file://` + filepath.ToSlash(filepath.Join(dir, "images", "ch3.png")) + `"></p>`
+	chapters := []ChapterData{{ID: "1", Chapter: "1", Body: body}}
+
+	var buf bytes.Buffer
+	exporter := New(Options{AssetsDir: dir})
+	if err := exporter.Export(context.Background(), chapters, &buf); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	files := readZipFiles(t, buf.Bytes())
+	var media int
+	for name := range files {
+		if strings.HasPrefix(name, "word/media/") {
+			media++
+		}
+	}
+	// file:// กับ path relative ชี้ไฟล์เดียวกันแต่ src ต่างกัน จึงได้ 3 ไฟล์
+	if media != 3 {
+		t.Errorf("got %d media parts, want 3", media)
+	}
+	doc := files["word/document.xml"]
+	if !strings.Contains(doc, "ภาพที่ 1") || !strings.Contains(doc, `<w:jc w:val="center">`) {
+		t.Errorf("figure caption or alignment missing from local image")
+	}
+	if summary := exporter.Summary(); summary.CacheHits != 0 || summary.CacheMisses != 0 {
+		t.Errorf("local images should not touch the cache: %+v", summary)
+	}
+}
@@ -0,0 +1,123 @@
+package exportdocx
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PageSetup กำหนดขนาดหน้ากระดาษและขอบ หน่วยเป็น twips (1/1440 นิ้ว)
+// ใช้ ParsePageSize และ ParseLength แปลงจาก mm, cm, in หรือ pt
+// ค่า 0 ของขนาดกระดาษ = A4 แนวตั้ง ส่วนขอบที่เป็น nil = 1 นิ้ว (ขอบ 0 ใช้ได้ เช่นงานพิมพ์เต็มหน้า)
+type PageSetup struct {
+	Width  int
+	Height int
+	// Landscape ใช้แนวนอน (ด้านยาวเป็นความกว้าง)
+	Landscape bool
+
+	Top    *int
+	Bottom *int
+	// Inside คือขอบด้านสัน (ขอบซ้ายเมื่อไม่ใช้ MirrorMargins) และ Outside คือขอบด้านนอก (ขอบขวา)
+	Inside  *int
+	Outside *int
+	// Gutter ระยะเผื่อเข้าเล่มที่บวกเพิ่มจากขอบด้านสัน
+	Gutter int
+	// MirrorMargins สลับขอบในและนอกระหว่างหน้าคู่และหน้าคี่ สำหรับพิมพ์สองหน้า
+	MirrorMargins bool
+}
+
+// ค่าเริ่มต้นของหน้ากระดาษ (A4 ขอบ 1 นิ้ว)
+const (
+	defaultPageWidth  = 11906
+	defaultPageHeight = 16838
+	defaultMargin     = 1440
+)
+
+// ขนาดกระดาษที่ตั้งชื่อไว้ (กว้าง x สูง เป็น twips แนวตั้ง)
+var namedPageSizes = map[string][2]int{
+	"a4":      {11906, 16838},
+	"a5":      {8391, 11906},
+	"a6":      {5953, 8391},
+	"b5":      {9979, 14173},
+	"b6":      {7087, 9979},
+	"letter":  {12240, 15840},
+	"5x8":     {7200, 11520},
+	"5.5x8.5": {7920, 12240},
+	"6x9":     {8640, 12960},
+}
+
+var lengthRegex = regexp.MustCompile(`^\s*(\d+(?:\.\d+)?)\s*(mm|cm|in|pt)?\s*$`)
+
+// ParseLength แปลงความยาวเช่น "20mm", "2.5cm", "0.75in" หรือ "72pt" เป็น twips
+// 0 ไม่ต้องมีหน่วย
+func ParseLength(s string) (int, error) {
+	m := lengthRegex.FindStringSubmatch(strings.ToLower(s))
+	if m != nil && m[2] == "" && lengthToTwips(m[1], "") == 0 {
+		return 0, nil
+	}
+	if m == nil || m[2] == "" {
+		return 0, fmt.Errorf("invalid length %q (use mm, cm, in or pt)", s)
+	}
+	return lengthToTwips(m[1], m[2]), nil
+}
+
+func lengthToTwips(value, unit string) int {
+	v, _ := strconv.ParseFloat(value, 64)
+	switch unit {
+	case "mm":
+		v = v * 1440 / 25.4
+	case "cm":
+		v = v * 1440 / 2.54
+	case "in":
+		v = v * 1440
+	case "pt":
+		v = v * 20
+	}
+	return int(math.Round(v))
+}
+
+// ParsePageSize แปลงชื่อขนาดกระดาษ (A4, A5, A6, B5, B6, Letter, 5x8, 5.5x8.5, 6x9)
+// หรือขนาดแบบ "148x210mm", "6inx9in" เป็นความกว้างและความสูงเป็น twips
+func ParsePageSize(s string) (width, height int, err error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if size, ok := namedPageSizes[name]; ok {
+		return size[0], size[1], nil
+	}
+
+	w, h, ok := strings.Cut(name, "x")
+	if !ok {
+		return 0, 0, fmt.Errorf("unknown page size %q", s)
+	}
+	wm := lengthRegex.FindStringSubmatch(w)
+	hm := lengthRegex.FindStringSubmatch(h)
+	if wm == nil || hm == nil || hm[2] == "" {
+		return 0, 0, fmt.Errorf("invalid page size %q (use a name or WxH with mm, cm, in or pt)", s)
+	}
+	// "148x210mm" ใช้หน่วยของด้านหลังกับทั้งสองด้าน
+	if wm[2] == "" {
+		wm[2] = hm[2]
+	}
+	return lengthToTwips(wm[1], wm[2]), lengthToTwips(hm[1], hm[2]), nil
+}
+
+// pageSetup คืน PageSetup ที่เติมค่าเริ่มต้นและจัดแนวกระดาษแล้ว (ขอบทุกด้านไม่เป็น nil)
+func (e *Exporter) pageSetup() PageSetup {
+	page := e.opts.Page
+	if page.Width <= 0 || page.Height <= 0 {
+		page.Width, page.Height = defaultPageWidth, defaultPageHeight
+	}
+	if page.Landscape && page.Width < page.Height {
+		page.Width, page.Height = page.Height, page.Width
+	}
+	// คัดลอกค่าเพื่อไม่ให้แก้ Options.Page ของผู้เรียก
+	for _, margin := range []**int{&page.Top, &page.Bottom, &page.Inside, &page.Outside} {
+		value := defaultMargin
+		if *margin != nil {
+			value = **margin
+		}
+		*margin = &value
+	}
+	return page
+}
@@ -0,0 +1,124 @@
+package exportdocx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLength(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"25.4mm", 1440},
+		{"2.54cm", 1440},
+		{"1in", 1440},
+		{"0.75in", 1080},
+		{"72pt", 1440},
+		{" 20 MM ", 1134},
+		{"0", 0},
+		{"0mm", 0},
+	}
+	for _, tt := range tests {
+		got, err := ParseLength(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseLength(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "20", "abc", "-5mm", "10px"} {
+		if _, err := ParseLength(bad); err == nil {
+			t.Errorf("ParseLength(%q) should fail", bad)
+		}
+	}
+}
+
+func TestParsePageSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		w, h int
+	}{
+		{"A4", 11906, 16838},
+		{"a5", 8391, 11906},
+		{"B6", 7087, 9979},
+		{"6x9", 8640, 12960},
+		{"148x210mm", 8391, 11906},
+		{"6inx9in", 8640, 12960},
+		{"5.25x8in", 7560, 11520},
+	}
+	for _, tt := range tests {
+		w, h, err := ParsePageSize(tt.in)
+		if err != nil || w != tt.w || h != tt.h {
+			t.Errorf("ParsePageSize(%q) = %dx%d, %v; want %dx%d", tt.in, w, h, err, tt.w, tt.h)
+		}
+	}
+	for _, bad := range []string{"A3", "148x210", "x9in"} {
+		if _, _, err := ParsePageSize(bad); err == nil {
+			t.Errorf("ParsePageSize(%q) should fail", bad)
+		}
+	}
+}
+
+func TestPageSetupSectPr(t *testing.T) {
+	w, h, _ := ParsePageSize("A5")
+	margin, _ := ParseLength("15mm")
+	inside, _ := ParseLength("20mm")
+	gutter, _ := ParseLength("5mm")
+	e := New(Options{Page: PageSetup{
+		Width: w, Height: h,
+		Top: &margin, Bottom: &margin, Inside: &inside, Outside: &margin, Gutter: gutter,
+		MirrorMargins: true,
+	}})
+
+	sectPr := e.pageSectPr()
+	if sectPr.PgSz.W != "8391" || sectPr.PgSz.H != "11906" || sectPr.PgSz.Orient != "" {
+		t.Errorf("PgSz = %+v", sectPr.PgSz)
+	}
+	if sectPr.PgMar.Left != "1134" || sectPr.PgMar.Right != "850" || sectPr.PgMar.Gutter != "283" {
+		t.Errorf("PgMar = %+v", sectPr.PgMar)
+	}
+	// ความกว้างข้อความ = 8391 - 1134 - 850 - 283 = 6124 twips = 408 px
+	if got := e.textWidthPx(); got != 408 {
+		t.Errorf("textWidthPx() = %d, want 408", got)
+	}
+
+	landscape := New(Options{Page: PageSetup{Landscape: true}}).pageSectPr()
+	if landscape.PgSz.W != "16838" || landscape.PgSz.H != "11906" || landscape.PgSz.Orient != "landscape" {
+		t.Errorf("landscape PgSz = %+v", landscape.PgSz)
+	}
+}
+
+func TestExportPageSetup(t *testing.T) {
+	w, h, _ := ParsePageSize("6x9")
+	opts := Options{Page: PageSetup{Width: w, Height: h, MirrorMargins: true}}
+	doc := exportDocument(t, []ChapterData{{ID: "1", Chapter: "1", Body: "<p>x</p>"}}, opts)
+	if !strings.Contains(doc, `<w:pgSz w:w="8640" w:h="12960">`) {
+		t.Errorf("document.xml missing 6x9 page size")
+	}
+
+	wide := 5000
+	opts.Page.Inside, opts.Page.Outside = &wide, &wide
+	if err := New(opts).Export(t.Context(), nil, &strings.Builder{}); err == nil {
+		t.Error("margins wider than the page should be rejected")
+	}
+}
+
+func TestZeroPageMargins(t *testing.T) {
+	zero := 0
+	e := New(Options{Page: PageSetup{Top: &zero, Bottom: &zero, Inside: &zero, Outside: &zero}})
+	if got := e.pageSectPr().PgMar; got.Top != "0" || got.Bottom != "0" || got.Left != "0" || got.Right != "0" {
+		t.Errorf("PgMar = %+v, want zero margins", got)
+	}
+	if got := e.textWidthTwips(); got != defaultPageWidth {
+		t.Errorf("textWidthTwips() = %d, want full page width %d", got, defaultPageWidth)
+	}
+
+	// ขอบที่ไม่ได้กำหนดยังเป็น 1 นิ้ว
+	if got := New(Options{Page: PageSetup{Top: &zero}}).pageSectPr().PgMar; got.Top != "0" || got.Bottom != "1440" || got.Left != "1440" {
+		t.Errorf("PgMar = %+v, want only top margin zero", got)
+	}
+
+	negative := -10
+	if err := New(Options{Page: PageSetup{Inside: &negative}}).Export(t.Context(), nil, &strings.Builder{}); err == nil {
+		t.Error("negative margins should be rejected")
+	}
+}
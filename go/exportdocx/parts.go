@@ -0,0 +1,524 @@
+package exportdocx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"slices"
+)
+
+// contentTypeOverride คือ Override ของ part ที่มีหรือไม่มีตาม Options
+type contentTypeOverride struct {
+	PartName    string
+	ContentType string
+}
+
+// ฟังก์ชันสร้างไฟล์ DOCX พื้นฐาน
+func createContentTypes(zipWriter *zip.Writer, overrides []contentTypeOverride) error {
+	w, err := zipWriter.Create("[Content_Types].xml")
+	if err != nil {
+		return err
+	}
+
+	content := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+    <Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+    <Default Extension="xml" ContentType="application/xml"/>
+    <Default Extension="png" ContentType="image/png"/>
+    <Default Extension="jpg" ContentType="image/jpeg"/>
+    <Default Extension="jpeg" ContentType="image/jpeg"/>
+    <Default Extension="gif" ContentType="image/gif"/>
+    <Default Extension="bmp" ContentType="image/bmp"/>
+    <Default Extension="tiff" ContentType="image/tiff"/>
+    <Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+    <Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>
+    <Override PartName="/word/settings.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.settings+xml"/>
+    <Override PartName="/docProps/app.xml" ContentType="application/vnd.openxmlformats-officedocument.extended-properties+xml"/>
+    <Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>`
+	for _, o := range overrides {
+		content += `
+    <Override PartName="` + o.PartName + `" ContentType="` + o.ContentType + `"/>`
+	}
+	content += `
+</Types>`
+
+	_, err = w.Write([]byte(content))
+	return err
+}
+
+func createRels(zipWriter *zip.Writer) error {
+	w, err := zipWriter.Create("_rels/.rels")
+	if err != nil {
+		return err
+	}
+
+	content := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+    <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+    <Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>
+    <Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/extended-properties" Target="docProps/app.xml"/>
+</Relationships>`
+
+	_, err = w.Write([]byte(content))
+	return err
+}
+
+func createApp(zipWriter *zip.Writer) error {
+	w, err := zipWriter.Create("docProps/app.xml")
+	if err != nil {
+		return err
+	}
+
+	content := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties">
+    <Application>CSV to DOCX Converter</Application>
+    <DocSecurity>0</DocSecurity>
+    <ScaleCrop>false</ScaleCrop>
+    <SharedDoc>false</SharedDoc>
+    <HyperlinksChanged>false</HyperlinksChanged>
+    <AppVersion>1.0</AppVersion>
+</Properties>`
+
+	_, err = w.Write([]byte(content))
+	return err
+}
+
+func createCore(zipWriter *zip.Writer, title string) error {
+	w, err := zipWriter.Create("docProps/core.xml")
+	if err != nil {
+		return err
+	}
+
+	if title == "" {
+		title = "Document from CSV"
+	}
+	var escapedTitle bytes.Buffer
+	if err := xml.EscapeText(&escapedTitle, []byte(title)); err != nil {
+		return err
+	}
+
+	content := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/" xmlns:dcmitype="http://purl.org/dc/dcmitype/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+    <dc:title>` + escapedTitle.String() + `</dc:title>
+    <dc:creator>CSV to DOCX Converter</dc:creator>
+    <dcterms:created xsi:type="dcterms:W3CDTF">2024-01-01T00:00:00Z</dcterms:created>
+    <dcterms:modified xsi:type="dcterms:W3CDTF">2024-01-01T00:00:00Z</dcterms:modified>
+</cp:coreProperties>`
+
+	_, err = w.Write([]byte(content))
+	return err
+}
+
+// documentSettings คือค่าใน settings.xml ที่เปลี่ยนตาม Options
+type documentSettings struct {
+	UpdateFields      bool // Word อัปเดต field ทั้งหมด (เช่น TOC) ตอนเปิดไฟล์
+	EvenAndOddHeaders bool // หน้าคู่และหน้าคี่ใช้ header/footer ต่างกัน
+	MirrorMargins     bool // สลับขอบในและนอกระหว่างหน้าคู่และหน้าคี่
+}
+
+// createSettings เขียน settings.xml (element ต้องเรียงตามลำดับใน schema)
+func createSettings(zipWriter *zip.Writer, settings documentSettings) error {
+	w, err := zipWriter.Create("word/settings.xml")
+	if err != nil {
+		return err
+	}
+
+	content := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:settings xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`
+	if settings.MirrorMargins {
+		content += `
+    <w:mirrorMargins/>`
+	}
+	content += `
+    <w:defaultTabStop w:val="720"/>`
+	if settings.EvenAndOddHeaders {
+		content += `
+    <w:evenAndOddHeaders/>`
+	}
+	content += `
+    <w:characterSpacingControl w:val="doNotCompress"/>`
+	if settings.UpdateFields {
+		content += `
+    <w:updateFields w:val="true"/>`
+	}
+	content += `
+    <w:compat>
+        <w:compatSetting w:name="compatibilityMode" w:uri="http://schemas.microsoft.com/office/word" w:val="15"/>
+    </w:compat>
+</w:settings>`
+
+	_, err = w.Write([]byte(content))
+	return err
+}
+
+func createStyles(zipWriter *zip.Writer, typo Typography, styles []builtinStyle) error {
+	w, err := zipWriter.Create("word/styles.xml")
+	if err != nil {
+		return err
+	}
+
+	content := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <w:docDefaults>
+    <w:rPrDefault>
+      ` + typo.rPrDefault() + `
+        </w:rPrDefault>
+        <w:pPrDefault>
+            <w:pPr>
+                <w:spacing w:after="120" w:line="276" w:lineRule="auto"/>
+            </w:pPr>
+        </w:pPrDefault>
+    </w:docDefaults>
+    `
+	for _, style := range styles {
+		content += "\n" + style.XML + "\n"
+	}
+	content += `</w:styles>`
+
+	_, err = w.Write([]byte(content))
+	return err
+}
+
+// builtinStyle คือ style ที่ converter อ้างถึงด้วย styleId
+type builtinStyle struct {
+	ID  string
+	XML string
+}
+
+// builtinStyles คือ style ทั้งหมดที่ converter ใช้ เรียงตามลำดับใน styles.xml
+// เมื่อใช้ ReferenceDocx style ที่ไม่มีในไฟล์ต้นแบบจะถูกเติมจากรายการนี้
+// Typography.Justification เป็นการจัดแนวของ BodyText
+func builtinStyles(typo Typography) []builtinStyle {
+	bodyJc := ""
+	if typo.Justification != "" {
+		bodyJc = `
+            <w:jc w:val="` + typo.Justification + `"/>`
+	}
+
+	styles := []builtinStyle{
+		{"Normal", `    <w:style w:type="paragraph" w:styleId="Normal">
+        <w:name w:val="Normal"/>
+        <w:qFormat/>
+        <w:pPr>
+            <w:spacing w:after="120"/>
+        </w:pPr>
+    </w:style>`},
+		{"DefaultParagraphFont", `    <w:style w:type="character" w:default="1" w:styleId="DefaultParagraphFont">
+        <w:name w:val="Default Paragraph Font"/>
+        <w:uiPriority w:val="1"/>
+        <w:semiHidden/>
+        <w:unhideWhenUsed/>
+    </w:style>`},
+		{"BodyText", `    <w:style w:type="paragraph" w:styleId="BodyText">
+        <w:name w:val="Body Text"/>
+        <w:basedOn w:val="Normal"/>
+        <w:uiPriority w:val="99"/>
+        <w:qFormat/>
+        <w:pPr>
+            <w:spacing w:after="120"/>` + bodyJc + `
+        </w:pPr>
+    </w:style>`},
+		{"BodyTextIndent", `    <w:style w:type="paragraph" w:styleId="BodyTextIndent">
+        <w:name w:val="Body Text Indent"/>
+        <w:basedOn w:val="BodyText"/>
+        <w:uiPriority w:val="99"/>
+        <w:qFormat/>
+        <w:pPr>
+            <w:ind w:firstLine="720"/>
+        </w:pPr>
+    </w:style>`},
+		{"ListParagraph", `    <w:style w:type="paragraph" w:styleId="ListParagraph">
+        <w:name w:val="List Paragraph"/>
+        <w:basedOn w:val="BodyText"/>
+        <w:uiPriority w:val="34"/>
+        <w:qFormat/>
+        <w:pPr>
+            <w:spacing w:after="60"/>
+            <w:contextualSpacing/>
+        </w:pPr>
+    </w:style>`},
+		{"Title", `    <w:style w:type="paragraph" w:styleId="Title">
+        <w:name w:val="Title"/>
+        <w:basedOn w:val="Normal"/>
+        <w:next w:val="Subtitle"/>
+        <w:uiPriority w:val="10"/>
+        <w:qFormat/>
+        <w:pPr>
+            <w:spacing w:before="3600" w:after="480"/>
+            <w:jc w:val="center"/>
+        </w:pPr>
+        <w:rPr>
+            <w:b/>
+            <w:bCs/>
+            <w:sz w:val="56"/>
+            <w:szCs w:val="56"/>
+        </w:rPr>
+    </w:style>`},
+		{"Subtitle", `    <w:style w:type="paragraph" w:styleId="Subtitle">
+        <w:name w:val="Subtitle"/>
+        <w:basedOn w:val="Normal"/>
+        <w:next w:val="Normal"/>
+        <w:uiPriority w:val="11"/>
+        <w:qFormat/>
+        <w:pPr>
+            <w:jc w:val="center"/>
+        </w:pPr>
+        <w:rPr>
+            <w:sz w:val="32"/>
+            <w:szCs w:val="32"/>
+        </w:rPr>
+    </w:style>`},
+		{"Heading1", `    <w:style w:type="paragraph" w:styleId="Heading1">
+        <w:name w:val="heading 1"/>
+        <w:basedOn w:val="Normal"/>
+        <w:next w:val="Normal"/>
+        <w:link w:val="Heading1Char"/>
+        <w:uiPriority w:val="9"/>
+        <w:qFormat/>
+        <w:pPr>
+            <w:keepNext/>
+            <w:keepLines/>
+            <w:spacing w:before="480" w:after="240"/>
+            <w:outlineLvl w:val="0"/>
+        </w:pPr>
+        <w:rPr>
+            <w:b/>
+            <w:bCs/>
+            <w:sz w:val="32"/>
+            <w:szCs w:val="32"/>
+        </w:rPr>
+    </w:style>`},
+		{"Heading1Char", `    <w:style w:type="character" w:styleId="Heading1Char" w:customStyle="1">
+        <w:name w:val="Heading 1 Char"/>
+        <w:basedOn w:val="DefaultParagraphFont"/>
+        <w:link w:val="Heading1"/>
+        <w:uiPriority w:val="9"/>
+        <w:rPr>
+            <w:b/>
+            <w:bCs/>
+            <w:sz w:val="32"/>
+            <w:szCs w:val="32"/>
+        </w:rPr>
+    </w:style>`},
+		{"Quote", `    <w:style w:type="paragraph" w:styleId="Quote">
+        <w:name w:val="Quote"/>
+        <w:basedOn w:val="BodyText"/>
+        <w:next w:val="BodyText"/>
+        <w:uiPriority w:val="29"/>
+        <w:qFormat/>
+        <w:pPr>
+            <w:ind w:left="720" w:right="720"/>
+        </w:pPr>
+        <w:rPr>
+            <w:i/>
+            <w:iCs/>
+        </w:rPr>
+    </w:style>`},
+		{"HTMLPreformatted", `    <w:style w:type="paragraph" w:styleId="HTMLPreformatted">
+        <w:name w:val="HTML Preformatted"/>
+        <w:basedOn w:val="Normal"/>
+        <w:uiPriority w:val="99"/>
+        <w:unhideWhenUsed/>
+        <w:pPr>
+            <w:spacing w:after="120" w:line="240" w:lineRule="auto"/>
+        </w:pPr>
+        <w:rPr>
+            <w:rFonts w:ascii="` + monospaceFont + `" w:hAnsi="` + monospaceFont + `"/>
+            <w:sz w:val="20"/>
+            <w:szCs w:val="20"/>
+        </w:rPr>
+    </w:style>`},
+		{"HTMLCode", `    <w:style w:type="character" w:styleId="HTMLCode">
+        <w:name w:val="HTML Code"/>
+        <w:basedOn w:val="DefaultParagraphFont"/>
+        <w:uiPriority w:val="99"/>
+        <w:unhideWhenUsed/>
+        <w:rPr>
+            <w:rFonts w:ascii="` + monospaceFont + `" w:hAnsi="` + monospaceFont + `"/>
+            <w:sz w:val="20"/>
+            <w:szCs w:val="20"/>
+        </w:rPr>
+    </w:style>`},
+		{"Caption", `    <w:style w:type="paragraph" w:styleId="Caption">
+        <w:name w:val="caption"/>
+        <w:basedOn w:val="Normal"/>
+        <w:next w:val="Normal"/>
+        <w:uiPriority w:val="35"/>
+        <w:unhideWhenUsed/>
+        <w:qFormat/>
+        <w:pPr>
+            <w:spacing w:after="240"/>
+        </w:pPr>
+        <w:rPr>
+            <w:i/>
+            <w:iCs/>
+            <w:sz w:val="20"/>
+            <w:szCs w:val="20"/>
+        </w:rPr>
+    </w:style>`},
+		{"TableNormal", `    <w:style w:type="table" w:default="1" w:styleId="TableNormal">
+        <w:name w:val="Normal Table"/>
+        <w:uiPriority w:val="99"/>
+        <w:semiHidden/>
+        <w:unhideWhenUsed/>
+        <w:tblPr>
+            <w:tblInd w:w="0" w:type="dxa"/>
+            <w:tblCellMar>
+                <w:top w:w="0" w:type="dxa"/>
+                <w:left w:w="108" w:type="dxa"/>
+                <w:bottom w:w="0" w:type="dxa"/>
+                <w:right w:w="108" w:type="dxa"/>
+            </w:tblCellMar>
+        </w:tblPr>
+    </w:style>`},
+		{"TableGrid", `    <w:style w:type="table" w:styleId="TableGrid">
+        <w:name w:val="Table Grid"/>
+        <w:basedOn w:val="TableNormal"/>
+        <w:uiPriority w:val="39"/>
+        <w:tblPr>
+            <w:tblBorders>
+                <w:top w:val="single" w:sz="4" w:space="0" w:color="auto"/>
+                <w:left w:val="single" w:sz="4" w:space="0" w:color="auto"/>
+                <w:bottom w:val="single" w:sz="4" w:space="0" w:color="auto"/>
+                <w:right w:val="single" w:sz="4" w:space="0" w:color="auto"/>
+                <w:insideH w:val="single" w:sz="4" w:space="0" w:color="auto"/>
+                <w:insideV w:val="single" w:sz="4" w:space="0" w:color="auto"/>
+            </w:tblBorders>
+        </w:tblPr>
+    </w:style>`},
+		{"TableText", `    <w:style w:type="paragraph" w:styleId="TableText" w:customStyle="1">
+        <w:name w:val="Table Text"/>
+        <w:basedOn w:val="Normal"/>
+        <w:uiPriority w:val="99"/>
+        <w:qFormat/>
+        <w:pPr>
+            <w:spacing w:before="40" w:after="40"/>
+        </w:pPr>
+    </w:style>`},
+		{"TableHeading", `    <w:style w:type="paragraph" w:styleId="TableHeading" w:customStyle="1">
+        <w:name w:val="Table Heading"/>
+        <w:basedOn w:val="TableText"/>
+        <w:uiPriority w:val="99"/>
+        <w:qFormat/>
+        <w:pPr>
+            <w:keepNext/>
+            <w:jc w:val="center"/>
+        </w:pPr>
+        <w:rPr>
+            <w:b/>
+            <w:bCs/>
+        </w:rPr>
+    </w:style>`},
+		{"Header", `    <w:style w:type="paragraph" w:styleId="Header">
+        <w:name w:val="header"/>
+        <w:basedOn w:val="Normal"/>
+        <w:uiPriority w:val="99"/>
+        <w:unhideWhenUsed/>
+        <w:pPr>
+            <w:spacing w:after="0" w:line="240" w:lineRule="auto"/>
+        </w:pPr>
+        <w:rPr>
+            <w:sz w:val="18"/>
+            <w:szCs w:val="18"/>
+        </w:rPr>
+    </w:style>`},
+		{"Footer", `    <w:style w:type="paragraph" w:styleId="Footer">
+        <w:name w:val="footer"/>
+        <w:basedOn w:val="Normal"/>
+        <w:uiPriority w:val="99"/>
+        <w:unhideWhenUsed/>
+        <w:pPr>
+            <w:spacing w:after="0" w:line="240" w:lineRule="auto"/>
+        </w:pPr>
+        <w:rPr>
+            <w:sz w:val="18"/>
+            <w:szCs w:val="18"/>
+        </w:rPr>
+    </w:style>`},
+		{"TOCHeading", `    <w:style w:type="paragraph" w:styleId="TOCHeading">
+        <w:name w:val="TOC Heading"/>
+        <w:basedOn w:val="Heading1"/>
+        <w:next w:val="Normal"/>
+        <w:uiPriority w:val="39"/>
+        <w:unhideWhenUsed/>
+        <w:qFormat/>
+        <w:pPr>
+            <w:outlineLvl w:val="9"/>
+        </w:pPr>
+    </w:style>`},
+		{"TOC1", `    <w:style w:type="paragraph" w:styleId="TOC1">
+        <w:name w:val="toc 1"/>
+        <w:basedOn w:val="Normal"/>
+        <w:next w:val="Normal"/>
+        <w:uiPriority w:val="39"/>
+        <w:unhideWhenUsed/>
+        <w:pPr>
+            <w:spacing w:after="100"/>
+        </w:pPr>
+    </w:style>`},
+		{"Emphasis", `    <w:style w:type="character" w:styleId="Emphasis">
+        <w:name w:val="Emphasis"/>
+        <w:basedOn w:val="DefaultParagraphFont"/>
+        <w:uiPriority w:val="20"/>
+        <w:qFormat/>
+        <w:rPr>
+            <w:i/>
+            <w:iCs/>
+        </w:rPr>
+    </w:style>`},
+		{"Hyperlink", `    <w:style w:type="character" w:styleId="Hyperlink">
+        <w:name w:val="Hyperlink"/>
+        <w:basedOn w:val="DefaultParagraphFont"/>
+        <w:uiPriority w:val="99"/>
+        <w:unhideWhenUsed/>
+        <w:rPr>
+            <w:color w:val="0563C1"/>
+            <w:u w:val="single"/>
+        </w:rPr>
+    </w:style>`},
+		{"Strong", `    <w:style w:type="character" w:styleId="Strong">
+        <w:name w:val="Strong"/>
+        <w:basedOn w:val="DefaultParagraphFont"/>
+        <w:uiPriority w:val="22"/>
+        <w:qFormat/>
+        <w:rPr>
+            <w:b/>
+            <w:bCs/>
+        </w:rPr>
+    </w:style>`},
+	}
+
+	// หัวข้อ <h2>-<h6> อยู่ต่อจาก Heading1Char
+	var headings []builtinStyle
+	for i, size := range []int{28, 26, 24, 22, 22} {
+		headings = append(headings, headingStyle(i+2, size))
+	}
+	i := slices.IndexFunc(styles, func(s builtinStyle) bool { return s.ID == "Heading1Char" }) + 1
+	return slices.Insert(styles, i, headings...)
+}
+
+// headingStyle สร้าง style ของหัวข้อระดับ level (outlineLvl = level - 1) ขนาด size half-point
+func headingStyle(level, size int) builtinStyle {
+	id := fmt.Sprintf("Heading%d", level)
+	return builtinStyle{id, fmt.Sprintf(`    <w:style w:type="paragraph" w:styleId="%s">
+        <w:name w:val="heading %d"/>
+        <w:basedOn w:val="Normal"/>
+        <w:next w:val="Normal"/>
+        <w:uiPriority w:val="9"/>
+        <w:unhideWhenUsed/>
+        <w:qFormat/>
+        <w:pPr>
+            <w:keepNext/>
+            <w:keepLines/>
+            <w:spacing w:before="360" w:after="120"/>
+            <w:outlineLvl w:val="%d"/>
+        </w:pPr>
+        <w:rPr>
+            <w:b/>
+            <w:bCs/>
+            <w:sz w:val="%d"/>
+            <w:szCs w:val="%d"/>
+        </w:rPr>
+    </w:style>`, id, level, level-1, size, size)}
+}
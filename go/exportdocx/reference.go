@@ -0,0 +1,505 @@
+package exportdocx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const officeRelationships = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/"
+
+// referenceDocx คือ part ที่นำมาใช้ซ้ำจาก DOCX ต้นแบบ (Options.ReferenceDocx)
+type referenceDocx struct {
+	styles   []byte
+	settings []byte // nil = ใช้ settings ของ converter
+	// parts คือ theme, fontTable, numbering และ part ที่ part เหล่านั้นอ้างถึง (เช่นฟอนต์ที่ฝังไว้)
+	parts []referencePart
+}
+
+// referencePart คือ part หนึ่งที่คัดลอกจากไฟล์ต้นแบบ
+type referencePart struct {
+	Name        string // ชื่อใน zip ของไฟล์ที่สร้าง เช่น word/theme/theme1.xml
+	ContentType string
+	Data        []byte
+	// RelType คือ type ของ relationship จาก document.xml ("" = part ที่ part อื่นอ้างถึง)
+	RelType string
+}
+
+// part ของไฟล์ต้นแบบที่ document.xml อ้างถึงและนำมาใช้ซ้ำ ตามชื่อที่เขียนในไฟล์ที่สร้าง
+var referencePartNames = map[string]string{
+	"theme":     "word/theme/theme1.xml",
+	"fontTable": "word/fontTable.xml",
+	"numbering": "word/numbering.xml",
+}
+
+// referenceReader อ่าน part จาก zip ของไฟล์ต้นแบบ
+type referenceReader struct {
+	files        map[string]*zip.File
+	defaults     map[string]string // นามสกุล -> content type
+	overrides    map[string]string // part name (ไม่มี / นำหน้า) -> content type
+	dependencies map[string]string // part ที่ถูกอ้างถึง -> ชื่อใหม่ในไฟล์ที่สร้าง
+}
+
+// loadReferenceDocx อ่าน styles, theme, fontTable, numbering และ settings จาก DOCX ต้นแบบ
+func loadReferenceDocx(filename string) (*referenceDocx, error) {
+	zr, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	r := &referenceReader{
+		files:        make(map[string]*zip.File),
+		defaults:     make(map[string]string),
+		overrides:    make(map[string]string),
+		dependencies: make(map[string]string),
+	}
+	for _, f := range zr.File {
+		r.files[f.Name] = f
+	}
+	if err := r.readContentTypes(); err != nil {
+		return nil, err
+	}
+
+	// หา document.xml จาก _rels/.rels แล้วอ่าน relationships ของมัน
+	rootRels, err := r.readRels("")
+	if err != nil {
+		return nil, err
+	}
+	mainPart := ""
+	for _, rel := range rootRels {
+		if rel.Type == officeRelationships+"officeDocument" {
+			mainPart = resolvePartName("", rel.Target)
+		}
+	}
+	if mainPart == "" {
+		return nil, errors.New("not a DOCX file (no officeDocument relationship)")
+	}
+	docRels, err := r.readRels(mainPart)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := &referenceDocx{}
+	for _, rel := range docRels {
+		if rel.TargetMode == "External" {
+			continue
+		}
+		name := resolvePartName(mainPart, rel.Target)
+		kind := strings.TrimPrefix(rel.Type, officeRelationships)
+		switch kind {
+		case "styles":
+			if ref.styles, err = r.read(name); err != nil {
+				return nil, err
+			}
+		case "settings":
+			if ref.settings, err = r.read(name); err != nil {
+				return nil, err
+			}
+		case "theme", "fontTable", "numbering":
+			parts, err := r.copyPart(name, referencePartNames[kind])
+			if err != nil {
+				return nil, err
+			}
+			parts[0].RelType = rel.Type
+			ref.parts = append(ref.parts, parts...)
+		}
+	}
+	if ref.styles == nil {
+		return nil, errors.New("no styles part")
+	}
+	if _, err := parseReferenceStyles(ref.styles); err != nil {
+		return nil, fmt.Errorf("styles part: %w", err)
+	}
+	return ref, nil
+}
+
+func (r *referenceReader) readContentTypes() error {
+	data, err := r.read("[Content_Types].xml")
+	if err != nil {
+		return err
+	}
+	var types struct {
+		Defaults []struct {
+			Extension   string `xml:"Extension,attr"`
+			ContentType string `xml:"ContentType,attr"`
+		} `xml:"Default"`
+		Overrides []struct {
+			PartName    string `xml:"PartName,attr"`
+			ContentType string `xml:"ContentType,attr"`
+		} `xml:"Override"`
+	}
+	if err := xml.Unmarshal(data, &types); err != nil {
+		return fmt.Errorf("[Content_Types].xml: %w", err)
+	}
+	for _, d := range types.Defaults {
+		r.defaults[strings.ToLower(d.Extension)] = d.ContentType
+	}
+	for _, o := range types.Overrides {
+		r.overrides[strings.TrimPrefix(o.PartName, "/")] = o.ContentType
+	}
+	return nil
+}
+
+func (r *referenceReader) read(name string) ([]byte, error) {
+	f, ok := r.files[name]
+	if !ok {
+		return nil, fmt.Errorf("missing part %s", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// readRels อ่าน relationships ของ part (ไม่มีไฟล์ .rels = ไม่มี relationship)
+func (r *referenceReader) readRels(partName string) ([]Relationship, error) {
+	relsName := relsPartName(partName)
+	if _, ok := r.files[relsName]; !ok {
+		return nil, nil
+	}
+	data, err := r.read(relsName)
+	if err != nil {
+		return nil, err
+	}
+	var rels Relationships
+	if err := xml.Unmarshal(data, &rels); err != nil {
+		return nil, fmt.Errorf("%s: %w", relsName, err)
+	}
+	return rels.Items, nil
+}
+
+func (r *referenceReader) contentType(name string) string {
+	if ct, ok := r.overrides[name]; ok {
+		return ct
+	}
+	if ct, ok := r.defaults[strings.TrimPrefix(strings.ToLower(path.Ext(name)), ".")]; ok {
+		return ct
+	}
+	return "application/octet-stream"
+}
+
+// copyPart คัดลอก part ไปเป็น newName พร้อม part ที่มันอ้างถึง
+// part ที่ถูกอ้างถึงย้ายไปไว้ใต้ word/reference/ เพื่อไม่ให้ชนกับรูปหรือ part ที่ converter สร้าง
+// และเขียน .rels ใหม่ด้วย target แบบ absolute
+func (r *referenceReader) copyPart(name, newName string) ([]referencePart, error) {
+	data, err := r.read(name)
+	if err != nil {
+		return nil, err
+	}
+	parts := []referencePart{{Name: newName, ContentType: r.contentType(name), Data: data}}
+
+	rels, err := r.readRels(name)
+	if err != nil || len(rels) == 0 {
+		return parts, err
+	}
+	for i, rel := range rels {
+		if rel.TargetMode == "External" {
+			continue
+		}
+		target := resolvePartName(name, rel.Target)
+		copied, seen := r.dependencies[target]
+		if !seen {
+			copied = "word/reference/" + strings.TrimPrefix(target, "word/")
+			r.dependencies[target] = copied
+			dependencyParts, err := r.copyPart(target, copied)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, dependencyParts...)
+		}
+		rels[i].Target = "/" + copied
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	relationships := Relationships{Xmlns: "http://schemas.openxmlformats.org/package/2006/relationships", Items: rels}
+	if err := encoder.Encode(relationships); err != nil {
+		return nil, err
+	}
+	parts = append(parts, referencePart{
+		Name:        relsPartName(newName),
+		ContentType: "application/vnd.openxmlformats-package.relationships+xml",
+		Data:        buf.Bytes(),
+	})
+	return parts, nil
+}
+
+// relsPartName คืนชื่อไฟล์ .rels ของ part เช่น word/document.xml -> word/_rels/document.xml.rels
+func relsPartName(partName string) string {
+	dir, file := path.Split(partName)
+	return dir + "_rels/" + file + ".rels"
+}
+
+// resolvePartName แปลง target ของ relationship เป็นชื่อใน zip
+func resolvePartName(source, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(path.Clean(target), "/")
+	}
+	return strings.TrimPrefix(path.Join(path.Dir(source), target), "/")
+}
+
+const wordprocessingML = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+
+// referenceStyles คือข้อมูลของ styles.xml ต้นแบบที่ใช้เติม style
+type referenceStyles struct {
+	IDs    map[string]bool // styleId ที่มีอยู่แล้ว
+	End    int             // ตำแหน่งของ tag ปิดของ root (w:styles)
+	WBound bool            // root ผูก prefix w กับ namespace ของ WordprocessingML
+}
+
+// parseReferenceStyles อ่าน styleId ด้วย encoding/xml จึงรองรับ quote ทั้งสองแบบและ prefix ที่ไม่ใช่ w
+func parseReferenceStyles(data []byte) (referenceStyles, error) {
+	parsed := referenceStyles{IDs: make(map[string]bool), End: -1}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	depth := 0
+	for {
+		offset := int(decoder.InputOffset())
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return parsed, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				if t.Name.Space != wordprocessingML || t.Name.Local != "styles" {
+					return parsed, fmt.Errorf("root element is %s, want w:styles", t.Name.Local)
+				}
+				for _, attr := range t.Attr {
+					if attr.Name.Space == "xmlns" && attr.Name.Local == "w" && attr.Value == wordprocessingML {
+						parsed.WBound = true
+					}
+				}
+			}
+			if t.Name.Space == wordprocessingML && t.Name.Local == "style" {
+				for _, attr := range t.Attr {
+					if attr.Name.Space == wordprocessingML && attr.Name.Local == "styleId" {
+						parsed.IDs[attr.Value] = true
+					}
+				}
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+			if depth == 0 {
+				parsed.End = offset
+			}
+		}
+	}
+	if parsed.End < 0 {
+		return parsed, errors.New("styles.xml has no root element")
+	}
+	return parsed, nil
+}
+
+// mergeStyles คืน styles.xml ของไฟล์ต้นแบบ โดยเติม style ที่ converter ใช้แต่ไม่มีในไฟล์ต้นแบบ
+func (ref *referenceDocx) mergeStyles(styles []builtinStyle) (content []byte, added []string, err error) {
+	existing, err := parseReferenceStyles(ref.styles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var fallback strings.Builder
+	for _, style := range styles {
+		if existing.IDs[style.ID] {
+			continue
+		}
+		styleXML := style.XML
+		if !existing.WBound {
+			// style ที่เติมเขียนด้วย prefix w จึงต้องประกาศ namespace เองเมื่อไฟล์ต้นแบบใช้ prefix อื่น
+			styleXML = strings.Replace(styleXML, "<w:style ", `<w:style xmlns:w="`+wordprocessingML+`" `, 1)
+		}
+		fallback.WriteString(styleXML + "\n")
+		added = append(added, style.ID)
+	}
+	if len(added) == 0 {
+		return ref.styles, nil, nil
+	}
+
+	content = append(content, ref.styles[:existing.End]...)
+	content = append(content, fallback.String()...)
+	return append(content, ref.styles[existing.End:]...), added, nil
+}
+
+// ลำดับของ element ใน w:settings ตาม schema (CT_Settings)
+var settingsElementOrder = []string{
+	"writeProtection", "view", "zoom", "removePersonalInformation", "removeDateAndTime",
+	"doNotDisplayPageBoundaries", "displayBackgroundShape", "printPostScriptOverText",
+	"printFractionalCharacterWidth", "printFormsData", "embedTrueTypeFonts", "embedSystemFonts",
+	"saveSubsetFonts", "saveFormsData", "mirrorMargins", "alignBordersAndEdges",
+	"bordersDoNotSurroundHeader", "bordersDoNotSurroundFooter", "gutterAtTop",
+	"hideSpellingErrors", "hideGrammaticalErrors", "activeWritingStyle", "proofState",
+	"formsDesign", "attachedTemplate", "linkStyles", "stylePaneFormatFilter",
+	"stylePaneSortMethod", "documentType", "mailMerge", "revisionView", "trackRevisions",
+	"doNotTrackMoves", "doNotTrackFormatting", "documentProtection", "autoFormatOverride",
+	"styleLockTheme", "styleLockQFSet", "defaultTabStop", "autoHyphenation",
+	"consecutiveHyphenLimit", "hyphenationZone", "doNotHyphenateCaps", "showEnvelope",
+	"summaryLength", "clickAndTypeStyle", "defaultTableStyle", "evenAndOddHeaders",
+	"bookFoldRevPrinting", "bookFoldPrinting", "bookFoldPrintingSheets",
+	"drawingGridHorizontalSpacing", "drawingGridVerticalSpacing",
+	"displayHorizontalDrawingGridEvery", "displayVerticalDrawingGridEvery",
+	"doNotUseMarginsForDrawingGridOrigin", "drawingGridHorizontalOrigin",
+	"drawingGridVerticalOrigin", "doNotShadeFormData", "noPunctuationKerning",
+	"characterSpacingControl", "printTwoOnOne", "strictFirstAndLastChars",
+	"noLineBreaksAfter", "noLineBreaksBefore", "savePreviewPicture",
+	"doNotValidateAgainstSchema", "saveInvalidXml", "ignoreMixedContent",
+	"alwaysShowPlaceholderText", "doNotDemarcateInvalidXml", "saveXmlDataOnly",
+	"useXSLTWhenSaving", "saveThroughXslt", "showXMLTags", "alwaysMergeEmptyNamespace",
+	"updateFields", "hdrShapeDefaults", "footnotePr", "endnotePr", "compat", "docVars",
+	"rsids", "mathPr", "attachedSchema", "themeFontLang", "clrSchemeMapping",
+	"doNotIncludeSubdocsInStats", "doNotAutoCompressPictures", "forceUpgrade", "captions",
+	"readModeInkLockDown", "smartTagType", "schemaLibrary", "shapeDefaults",
+	"doNotEmbedSmartTags", "decimalSymbol", "listSeparator",
+}
+
+// mergeSettings คืน settings.xml ของไฟล์ต้นแบบที่ปรับตาม Options
+// mirrorMargins และ evenAndOddHeaders เป็นไปตาม Options เสมอเพราะหน้ากระดาษและ header มาจาก Options
+// element ที่อ้างถึงไฟล์หรือ part ที่ไม่มีในเอกสารที่สร้าง (template, mail merge, footnote) ถูกตัดออก
+func (ref *referenceDocx) mergeSettings(settings documentSettings) []byte {
+	content := string(ref.settings)
+	for _, name := range removedSettingsElements {
+		content = removeSettingsElement(content, name)
+	}
+	if settings.MirrorMargins {
+		content = insertSettingsElement(content, "mirrorMargins", `<w:mirrorMargins/>`)
+	}
+	if settings.EvenAndOddHeaders {
+		content = insertSettingsElement(content, "evenAndOddHeaders", `<w:evenAndOddHeaders/>`)
+	}
+	if settings.UpdateFields {
+		content = removeSettingsElement(content, "updateFields")
+		content = insertSettingsElement(content, "updateFields", `<w:updateFields w:val="true"/>`)
+	}
+	return []byte(content)
+}
+
+// removedSettingsElements คือ element ใน settings ของไฟล์ต้นแบบที่ไม่คัดลอกมา
+var removedSettingsElements = []string{"attachedTemplate", "mailMerge", "footnote", "endnote", "mirrorMargins", "evenAndOddHeaders"}
+
+// settingsElementRegexes คือ regex ของ element ที่ mergeSettings ลบออกหรือเขียนใหม่ (รวมช่องว่างข้างหน้า)
+var settingsElementRegexes = func() map[string]*regexp.Regexp {
+	regexes := make(map[string]*regexp.Regexp)
+	for _, name := range append(removedSettingsElements, "updateFields") {
+		regexes[name] = regexp.MustCompile(`(?s)\s*<w:` + name + `(?:\s[^>]*?)?(?:/>|>.*?</w:` + name + `>)`)
+	}
+	return regexes
+}()
+
+// settingsStartTagRegex หา start tag ของ element ใน w:settings (group 1 = ชื่อ element)
+var settingsStartTagRegex = regexp.MustCompile(`<w:([A-Za-z]+)[\s/>]`)
+
+func removeSettingsElement(content, name string) string {
+	return settingsElementRegexes[name].ReplaceAllString(content, "")
+}
+
+// insertSettingsElement ใส่ element ก่อน element แรกที่ต้องอยู่หลังมันตาม schema
+func insertSettingsElement(content, name, element string) string {
+	pos := strings.LastIndex(content, "</w:settings>")
+	later := make(map[string]bool)
+	after := false
+	for _, other := range settingsElementOrder {
+		if other == name {
+			after = true
+		} else if after {
+			later[other] = true
+		}
+	}
+	for _, m := range settingsStartTagRegex.FindAllStringSubmatchIndex(content, -1) {
+		if m[0] >= pos {
+			break
+		}
+		if later[content[m[2]:m[3]]] {
+			pos = m[0]
+			break
+		}
+	}
+	if pos < 0 {
+		return content
+	}
+	return content[:pos] + element + content[pos:]
+}
+
+// referenceContentTypes คืน Override ของ part ที่คัดลอกจากไฟล์ต้นแบบ
+func (e *Exporter) referenceContentTypes() []contentTypeOverride {
+	if e.reference == nil {
+		return nil
+	}
+	var overrides []contentTypeOverride
+	for _, part := range e.reference.parts {
+		if strings.HasSuffix(part.Name, ".rels") {
+			continue
+		}
+		overrides = append(overrides, contentTypeOverride{PartName: "/" + part.Name, ContentType: part.ContentType})
+	}
+	return overrides
+}
+
+// writeReferenceParts เขียน part ที่คัดลอกจากไฟล์ต้นแบบ
+func (e *Exporter) writeReferenceParts(zipWriter *zip.Writer) error {
+	if e.reference == nil {
+		return nil
+	}
+	for _, part := range e.reference.parts {
+		if err := writePart(zipWriter, part.Name, part.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// referenceRelationships คืน relationship จาก document.xml ไปยัง theme, fontTable และ numbering
+func (e *Exporter) referenceRelationships() []Relationship {
+	if e.reference == nil {
+		return nil
+	}
+	var rels []Relationship
+	for _, part := range e.reference.parts {
+		if part.RelType == "" {
+			continue
+		}
+		rels = append(rels, Relationship{
+			Id:     e.nextRelID(),
+			Type:   part.RelType,
+			Target: strings.TrimPrefix(part.Name, "word/"),
+		})
+	}
+	return rels
+}
+
+// writeStyles เขียน styles.xml ของ converter หรือของไฟล์ต้นแบบที่เติม style ที่ขาดแล้ว
+func (e *Exporter) writeStyles(zipWriter *zip.Writer) error {
+	if e.reference == nil {
+		return createStyles(zipWriter, e.typography(), e.documentStyles())
+	}
+	content, added, err := e.reference.mergeStyles(e.documentStyles())
+	if err != nil {
+		return err
+	}
+	if len(added) > 0 {
+		e.logf("🎨 Added fallback styles missing from reference: %s\n", strings.Join(added, ", "))
+	}
+	return writePart(zipWriter, "word/styles.xml", content)
+}
+
+// writePart เขียนข้อมูลทั้งก้อนเป็น part หนึ่งใน zip
+func writePart(zipWriter *zip.Writer, name string, data []byte) error {
+	w, err := zipWriter.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
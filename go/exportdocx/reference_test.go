@@ -0,0 +1,200 @@
+package exportdocx
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeReferenceDocx สร้าง DOCX ต้นแบบขนาดเล็กจาก map ของ part
+func writeReferenceDocx(t *testing.T, parts map[string]string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(t.TempDir(), "reference.docx")
+	if err := os.WriteFile(filename, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func houseTemplateParts() map[string]string {
+	return map[string]string{
+		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+  <Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+  <Default Extension="xml" ContentType="application/xml"/>
+  <Default Extension="odttf" ContentType="application/vnd.openxmlformats-officedocument.obfuscatedFont"/>
+  <Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+  <Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>
+  <Override PartName="/word/settings.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.settings+xml"/>
+  <Override PartName="/word/theme/theme1.xml" ContentType="application/vnd.openxmlformats-officedocument.theme+xml"/>
+  <Override PartName="/word/fontTable.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.fontTable+xml"/>
+</Types>`,
+		"_rels/.rels": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+</Relationships>`,
+		"word/document.xml": `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body/></w:document>`,
+		"word/_rels/document.xml.rels": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+  <Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/theme" Target="theme/theme1.xml"/>
+  <Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/fontTable" Target="fontTable.xml"/>
+  <Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/settings" Target="settings.xml"/>
+</Relationships>`,
+		"word/styles.xml": `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>
+  <w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="House Heading"/></w:style>
+</w:styles>`,
+		"word/theme/theme1.xml":         `<a:theme xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" name="House"/>`,
+		"word/fontTable.xml":            `<w:fonts xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><w:font w:name="House Serif"><w:embedRegular r:id="rId1"/></w:font></w:fonts>`,
+		"word/_rels/fontTable.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/font" Target="fonts/font1.odttf"/></Relationships>`,
+		"word/fonts/font1.odttf":        "FONTDATA",
+		"word/settings.xml": `<w:settings xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+  <w:zoom w:percent="100"/>
+  <w:embedTrueTypeFonts/>
+  <w:attachedTemplate r:id="rId1"/>
+  <w:defaultTabStop w:val="567"/>
+  <w:evenAndOddHeaders/>
+  <w:footnotePr><w:footnote w:id="-1"/><w:footnote w:id="0"/></w:footnotePr>
+  <w:compat><w:compatSetting w:name="compatibilityMode" w:uri="http://schemas.microsoft.com/office/word" w:val="15"/></w:compat>
+</w:settings>`,
+	}
+}
+
+func TestExportWithReferenceDocx(t *testing.T) {
+	reference := writeReferenceDocx(t, houseTemplateParts())
+	chapters := []ChapterData{{ID: "1", Chapter: "บทที่ 1", Body: "<p>เนื้อหา</p>"}}
+
+	var buf bytes.Buffer
+	opts := Options{ReferenceDocx: reference, TOC: true, Page: PageSetup{MirrorMargins: true}}
+	if err := Export(context.Background(), chapters, &buf, opts); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	files := readZipFiles(t, buf.Bytes())
+
+	styles := files["word/styles.xml"]
+	if !strings.Contains(styles, "House Heading") || strings.Count(styles, `w:styleId="Heading1"`) != 1 {
+		t.Errorf("Heading1 from reference should be kept as is:\n%s", styles)
+	}
+	for _, id := range []string{"Heading1Char", "Header", "Footer", "TOCHeading", "TOC1"} {
+		if !strings.Contains(styles, `w:styleId="`+id+`"`) {
+			t.Errorf("fallback style %s not injected", id)
+		}
+	}
+
+	if files["word/theme/theme1.xml"] != houseTemplateParts()["word/theme/theme1.xml"] {
+		t.Errorf("theme not copied from reference")
+	}
+	if files["word/reference/fonts/font1.odttf"] != "FONTDATA" {
+		t.Errorf("embedded font not copied")
+	}
+	if !strings.Contains(files["word/_rels/fontTable.xml.rels"], `Target="/word/reference/fonts/font1.odttf"`) {
+		t.Errorf("fontTable rels not rewritten:\n%s", files["word/_rels/fontTable.xml.rels"])
+	}
+
+	contentTypes := files["[Content_Types].xml"]
+	for _, want := range []string{
+		`PartName="/word/theme/theme1.xml" ContentType="application/vnd.openxmlformats-officedocument.theme+xml"`,
+		`PartName="/word/fontTable.xml"`,
+		`PartName="/word/reference/fonts/font1.odttf" ContentType="application/vnd.openxmlformats-officedocument.obfuscatedFont"`,
+	} {
+		if !strings.Contains(contentTypes, want) {
+			t.Errorf("[Content_Types].xml missing %s", want)
+		}
+	}
+
+	rels := files["word/_rels/document.xml.rels"]
+	for _, want := range []string{`/relationships/theme" Target="theme/theme1.xml"`, `/relationships/fontTable" Target="fontTable.xml"`} {
+		if !strings.Contains(rels, want) {
+			t.Errorf("document.xml.rels missing %s", want)
+		}
+	}
+
+	settings := files["word/settings.xml"]
+	for _, unwanted := range []string{"attachedTemplate", "evenAndOddHeaders", "<w:footnote "} {
+		if strings.Contains(settings, unwanted) {
+			t.Errorf("settings.xml should not contain %s:\n%s", unwanted, settings)
+		}
+	}
+	for _, order := range [][2]string{
+		{"<w:embedTrueTypeFonts/>", "<w:mirrorMargins/>"},
+		{"<w:mirrorMargins/>", `<w:defaultTabStop w:val="567"/>`},
+		{`<w:updateFields w:val="true"/>`, "<w:footnotePr>"},
+	} {
+		first, second := strings.Index(settings, order[0]), strings.Index(settings, order[1])
+		if first < 0 || second < 0 || first > second {
+			t.Errorf("settings.xml should contain %s before %s:\n%s", order[0], order[1], settings)
+		}
+	}
+}
+
+func TestExportReferenceDocxErrors(t *testing.T) {
+	parts := houseTemplateParts()
+	delete(parts, "word/styles.xml")
+	noStyles := writeReferenceDocx(t, parts)
+	parts["word/styles.xml"] = `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:style>`
+	brokenStyles := writeReferenceDocx(t, parts)
+
+	for _, reference := range []string{filepath.Join(t.TempDir(), "missing.docx"), noStyles, brokenStyles} {
+		err := Export(context.Background(), nil, &bytes.Buffer{}, Options{ReferenceDocx: reference})
+		if err == nil || !strings.Contains(err.Error(), "reference docx") {
+			t.Errorf("Export with %s: got %v, want reference docx error", filepath.Base(reference), err)
+		}
+	}
+}
+
+func TestReferenceStylesWithOtherPrefixAndQuotes(t *testing.T) {
+	parts := houseTemplateParts()
+	// Heading1 ใช้ single quote และ Title อยู่ใต้ prefix อื่น จึงต้องไม่ถูกเติมซ้ำ
+	parts["word/styles.xml"] = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<ns0:styles xmlns:ns0="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+  <ns0:style ns0:type='paragraph' ns0:styleId='Heading1'><ns0:name ns0:val='House Heading'/></ns0:style>
+  <ns0:style ns0:type="paragraph" ns0:styleId="Title"><ns0:name ns0:val="House Title"/></ns0:style>
+</ns0:styles>`
+	reference := writeReferenceDocx(t, parts)
+
+	var buf bytes.Buffer
+	chapters := []ChapterData{{ID: "1", Chapter: "บทที่ 1", Body: "<p>เนื้อหา</p>"}}
+	if err := Export(context.Background(), chapters, &buf, Options{ReferenceDocx: reference}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	styles := readZipFiles(t, buf.Bytes())["word/styles.xml"]
+
+	for _, id := range []string{"Heading1", "Title"} {
+		if strings.Contains(styles, `w:styleId="`+id+`"`) {
+			t.Errorf("style %s from reference was injected again:\n%s", id, styles)
+		}
+	}
+	if !strings.Contains(styles, `w:styleId="Heading1Char"`) || !strings.HasSuffix(strings.TrimSpace(styles), "</ns0:styles>") {
+		t.Errorf("fallback styles not injected before </ns0:styles>:\n%s", styles)
+	}
+
+	// styles.xml ที่เติมแล้วต้องยังเป็น XML ที่ถูกต้อง (prefix w ถูกประกาศใน style ที่เติม)
+	merged, err := parseReferenceStyles([]byte(styles))
+	if err != nil {
+		t.Fatalf("merged styles.xml: %v", err)
+	}
+	for _, id := range []string{"Heading1", "Heading1Char", "Title", "TOC1"} {
+		if !merged.IDs[id] {
+			t.Errorf("merged styles.xml missing %s", id)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package exportdocx
+
+// sectionProperties คืน sectPr ของเอกสารพร้อม header/footer references
+// relationship ID ของ header/footer จัดสรรตอนเรียกครั้งแรกแล้วใช้ซ้ำทุก section
+// ID ของรูปจึงไม่จำเป็นต้องเรียงต่อกัน (โหมด section ต่อบทที่มีหน้าปกจะได้ ID ของ header/footer ก่อนรูปของบทแรก)
+func (e *Exporter) sectionProperties() SectPr {
+	sectPr := e.pageSectPr()
+
+	parts := e.headerFooterParts()
+	for i := range parts {
+		if parts[i].RelId == "" {
+			parts[i].RelId = e.nextRelID()
+		}
+		ref := HeaderFooterReference{Type: parts[i].Type, RelId: parts[i].RelId}
+		if parts[i].Kind == "header" {
+			sectPr.HeaderRefs = append(sectPr.HeaderRefs, ref)
+		} else {
+			sectPr.FooterRefs = append(sectPr.FooterRefs, ref)
+		}
+	}
+
+	if e.opts.DifferentFirstPage {
+		sectPr.TitlePg = &TitlePg{}
+	}
+	return sectPr
+}
+
+// sectionPerChapter คืน true ถ้าแต่ละบทเป็น section ของตัวเอง
+func (e *Exporter) sectionPerChapter() bool {
+	return e.opts.ChapterBreak == ChapterBreakNextPage || e.opts.ChapterBreak == ChapterBreakOddPage
+}
+
+// chapterSectionProperties คืน sectPr ของบทที่ index
+// บทแรกเริ่มเลขหน้าใหม่เมื่อมีหน้าปกหรือสารบัญนำหน้า บทอื่นเริ่มใหม่เมื่อเปิด RestartPageNumbers
+func (e *Exporter) chapterSectionProperties(index int, hasFrontMatter bool) SectPr {
+	sectPr := e.sectionProperties()
+	sectPr.Type = &SectType{Val: string(e.opts.ChapterBreak)}
+	if e.opts.RestartPageNumbers || (index == 0 && hasFrontMatter) {
+		sectPr.PgNumType = &PgNumType{Start: "1"}
+	}
+	return sectPr
+}
+
+// endSection จบ section ที่ paragraph สุดท้ายของ content ด้วย sectPr ระดับ paragraph
+// ถ้า element สุดท้ายไม่ใช่ paragraph จะเพิ่ม paragraph ว่างสำหรับ sectPr
+func endSection(content []interface{}, sectPr SectPr) []interface{} {
+	if len(content) > 0 {
+		if para, ok := content[len(content)-1].(Paragraph); ok {
+			// คัดลอก PPr เพราะบาง paragraph อาจใช้ pointer ร่วมกัน
+			props := PPr{}
+			if para.Props != nil {
+				props = *para.Props
+			}
+			props.SectPr = &sectPr
+			para.Props = &props
+			content[len(content)-1] = para
+			return content
+		}
+	}
+	return append(content, Paragraph{Props: &PPr{SectPr: &sectPr}})
+}
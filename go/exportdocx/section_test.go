@@ -0,0 +1,79 @@
+package exportdocx
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func exportDocument(t *testing.T, chapters []ChapterData, opts Options) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Export(context.Background(), chapters, &buf, opts); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	return readZipFiles(t, buf.Bytes())["word/document.xml"]
+}
+
+func TestExportSectionPerChapter(t *testing.T) {
+	chapters := []ChapterData{
+		{ID: "1", Chapter: "บทที่ 1", Body: "<p>หนึ่ง</p>"},
+		{ID: "2", Chapter: "บทที่ 2", Body: "<p>สอง</p>"},
+		{ID: "3", Chapter: "บทที่ 3", Body: "<p>สาม</p>"},
+	}
+
+	tests := []struct {
+		name      string
+		opts      Options
+		sections  int // จำนวน sectPr ทั้งหมด (รวม sectPr ของ body)
+		restarts  int
+		pageBreak bool
+	}{
+		{"page break", Options{}, 1, 0, true},
+		{"odd page continue", Options{ChapterBreak: ChapterBreakOddPage}, 3, 0, false},
+		{"next page restart", Options{ChapterBreak: ChapterBreakNextPage, RestartPageNumbers: true}, 3, 3, false},
+		{"front matter", Options{ChapterBreak: ChapterBreakOddPage, TOC: true}, 4, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := exportDocument(t, chapters, tt.opts)
+
+			if got := strings.Count(doc, "<w:sectPr>"); got != tt.sections {
+				t.Errorf("got %d sectPr, want %d", got, tt.sections)
+			}
+			if got := strings.Count(doc, `<w:pgNumType w:start="1">`); got != tt.restarts {
+				t.Errorf("got %d page number restarts, want %d", got, tt.restarts)
+			}
+			if got := strings.Contains(doc, `<w:br w:type="page">`); got != tt.pageBreak {
+				t.Errorf("page break paragraphs = %v, want %v", got, tt.pageBreak)
+			}
+			if tt.opts.ChapterBreak != "" && !strings.Contains(doc, `<w:type w:val="`+string(tt.opts.ChapterBreak)+`">`) {
+				t.Errorf("sectPr missing section type %s", tt.opts.ChapterBreak)
+			}
+		})
+	}
+}
+
+func TestExportSectionEndsOnLastParagraph(t *testing.T) {
+	chapters := []ChapterData{
+		{ID: "1", Chapter: "บทที่ 1", Body: "<p>จบบท</p>"},
+		{ID: "2", Chapter: "บทที่ 2", Body: "<p>x</p>"},
+	}
+	doc := exportDocument(t, chapters, Options{ChapterBreak: ChapterBreakNextPage})
+
+	// sectPr ต้องอยู่ใน pPr ของ paragraph สุดท้ายของบทที่ 1 ไม่ใช่ paragraph ว่างที่เพิ่มเข้ามา
+	sect := strings.Index(doc, "<w:sectPr>")
+	last := strings.LastIndex(doc[:sect], "<w:p>")
+	if !strings.Contains(doc[last:], "จบบท") || strings.Index(doc[last:], "จบบท") > strings.Index(doc[last:], "</w:p>") {
+		t.Errorf("section break is not attached to the chapter's last paragraph:\n%s", doc[last:sect])
+	}
+}
+
+func TestExportRejectsUnknownChapterBreak(t *testing.T) {
+	var buf bytes.Buffer
+	err := Export(context.Background(), nil, &buf, Options{ChapterBreak: "evenPage"})
+	if err == nil {
+		t.Fatal("expected error for unknown chapter break")
+	}
+}
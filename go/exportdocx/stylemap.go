@@ -0,0 +1,172 @@
+package exportdocx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// StyleRule ผูก element ที่ตรงกับ Selector กับ style ของ Word หรือ direct formatting
+//
+// Selector เป็น class (".indent-1"), tag กับ class ("p.scene-break") หรือ tag อย่างเดียว ("aside")
+// ใส่หลาย class ได้ เช่น "div.note.warning" ต้องมีครบทุก class
+// ParagraphStyle ใช้กับย่อหน้าที่สร้างจาก block element ส่วน CharacterStyle และ CSS
+// สืบทอดไปถึงข้อความข้างในเหมือน formatting ของ tag
+// ถ้าหลาย rule ตรงกัน rule ที่อยู่หลังมีผลกว่า และ style attribute ของ element มีผลกว่า rule
+type StyleRule struct {
+	Selector       string `json:"selector"`
+	ParagraphStyle string `json:"paragraphStyle,omitempty"` // styleId เช่น BodyTextIndent
+	CharacterStyle string `json:"characterStyle,omitempty"` // styleId เช่น Emphasis
+	// CSS คือ declaration แบบเดียวกับ style attribute เช่น "text-align: center; color: gray"
+	CSS string `json:"css,omitempty"`
+}
+
+// DefaultStyleRules คือ rule ที่ใช้เมื่อไม่ได้กำหนด Options.StyleRules
+// class="indent-a" ใช้ style BodyTextIndent (เยื้องบรรทัดแรก 0.5 นิ้ว)
+func DefaultStyleRules() []StyleRule {
+	return []StyleRule{
+		{Selector: ".indent-a", ParagraphStyle: "BodyTextIndent"},
+	}
+}
+
+// LoadStyleRules อ่านไฟล์ JSON รูปแบบ {"rules": [{"selector": ".indent-1", "paragraphStyle": "BodyTextIndent"}]}
+func LoadStyleRules(filename string) ([]StyleRule, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStyleRules(data)
+}
+
+// ParseStyleRules แปลง JSON ของ LoadStyleRules และตรวจ selector กับ styleId
+// rules ที่ว่างคืน slice ว่างที่ไม่ใช่ nil (ปิด DefaultStyleRules)
+func ParseStyleRules(data []byte) ([]StyleRule, error) {
+	var file struct {
+		Rules []StyleRule `json:"rules"`
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid style map: %w", err)
+	}
+	rules := make([]StyleRule, 0, len(file.Rules))
+	for _, rule := range file.Rules {
+		if _, err := compileStyleRule(rule); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// styleRule คือ StyleRule ที่แยก selector แล้ว
+type styleRule struct {
+	StyleRule
+	Tag     string
+	Classes []string
+}
+
+// styleRules คือ rule ทั้งหมดของ export ตามลำดับใน Options.StyleRules
+type styleRules []styleRule
+
+var (
+	selectorRegex     = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9]*)?((?:\.[a-zA-Z0-9_-]+)*)$`)
+	validStyleIDRegex = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+)
+
+func compileStyleRule(rule StyleRule) (styleRule, error) {
+	selector := strings.TrimSpace(rule.Selector)
+	m := selectorRegex.FindStringSubmatch(selector)
+	if selector == "" || m == nil {
+		return styleRule{}, fmt.Errorf("style rule %q: selector must be tag, .class or tag.class", rule.Selector)
+	}
+	for _, id := range []string{rule.ParagraphStyle, rule.CharacterStyle} {
+		if !validStyleIDRegex.MatchString(id) {
+			return styleRule{}, fmt.Errorf("style rule %q: invalid style ID %q", rule.Selector, id)
+		}
+	}
+	if rule.ParagraphStyle == "" && rule.CharacterStyle == "" && strings.TrimSpace(rule.CSS) == "" {
+		return styleRule{}, fmt.Errorf("style rule %q: needs paragraphStyle, characterStyle or css", rule.Selector)
+	}
+
+	compiled := styleRule{StyleRule: rule, Tag: strings.ToLower(m[1])}
+	if m[2] != "" {
+		compiled.Classes = strings.Split(m[2][1:], ".")
+	}
+	return compiled, nil
+}
+
+// compileStyleRules แยก selector ของทุก rule (nil = DefaultStyleRules)
+func compileStyleRules(rules []StyleRule) (styleRules, error) {
+	if rules == nil {
+		rules = DefaultStyleRules()
+	}
+	compiled := make(styleRules, 0, len(rules))
+	for _, rule := range rules {
+		c, err := compileStyleRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, c)
+	}
+	return compiled, nil
+}
+
+// match คืน rule ที่ตรงกับ element n ตามลำดับ (rules ที่เป็น nil ไม่ตรงกับอะไรเลย)
+func (rules *styleRules) match(n *html.Node) []styleRule {
+	if rules == nil || n.Type != html.ElementNode {
+		return nil
+	}
+	var matched []styleRule
+	for _, rule := range *rules {
+		if rule.Tag != "" && rule.Tag != n.Data {
+			continue
+		}
+		ok := true
+		for _, class := range rule.Classes {
+			ok = ok && hasClass(n, class)
+		}
+		if ok {
+			matched = append(matched, rule)
+		}
+	}
+	return matched
+}
+
+// customStyles คือ style ที่ rule อ้างถึงแต่ converter ไม่มี
+// สร้างเป็น style เปล่าที่สืบจาก BodyText หรือ DefaultParagraphFont เพื่อให้แก้ใน Word ได้ทีหลัง
+func (rules styleRules) customStyles(builtin []builtinStyle) []builtinStyle {
+	seen := make(map[string]bool)
+	for _, style := range builtin {
+		seen[style.ID] = true
+	}
+	var styles []builtinStyle
+	add := func(id, styleType, basedOn string) {
+		if id == "" || seen[id] {
+			return
+		}
+		seen[id] = true
+		styles = append(styles, builtinStyle{id, `    <w:style w:type="` + styleType + `" w:styleId="` + id + `" w:customStyle="1">
+        <w:name w:val="` + id + `"/>
+        <w:basedOn w:val="` + basedOn + `"/>
+        <w:uiPriority w:val="99"/>
+        <w:qFormat/>
+    </w:style>`})
+	}
+	for _, rule := range rules {
+		add(rule.ParagraphStyle, "paragraph", "BodyText")
+		add(rule.CharacterStyle, "character", "DefaultParagraphFont")
+	}
+	return styles
+}
+
+// documentStyles คือ style ทั้งหมดที่เขียนลง styles.xml (หรือเติมให้ไฟล์ต้นแบบ)
+func (e *Exporter) documentStyles() []builtinStyle {
+	styles := builtinStyles(e.typography())
+	return append(styles, e.styleRules.customStyles(styles)...)
+}
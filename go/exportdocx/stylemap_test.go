@@ -0,0 +1,91 @@
+package exportdocx
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestExportStyleRules(t *testing.T) {
+	rules, err := ParseStyleRules([]byte(`{"rules": [
+		{"selector": ".indent-1", "paragraphStyle": "BodyTextIndent"},
+		{"selector": "p.scene-break", "paragraphStyle": "SceneBreak", "css": "text-align: center; margin-top: 12pt"},
+		{"selector": ".system-msg", "characterStyle": "SystemMessage"},
+		{"selector": "div.author-note", "css": "font-style: italic; color: gray"}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParseStyleRules: %v", err)
+	}
+	chapters := []ChapterData{{ID: "1", Chapter: "บทที่ 1", Body: `<p class="indent-1">เยื้อง</p>` +
+		`<p class="scene-break">***</p><div class="scene-break">div</div>` +
+		`<p>ระบบ: <span class="system-msg">เลเวลอัป</span></p>` +
+		`<div class="author-note" style="color: #00f">หมายเหตุ</div>` +
+		`<p class="indent-a">ไม่มี rule</p>`}}
+
+	var buf bytes.Buffer
+	if err := Export(context.Background(), chapters, &buf, Options{StyleRules: rules}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	files := readZipFiles(t, buf.Bytes())
+	doc := compactXML(files["word/document.xml"])
+
+	for _, want := range []string{
+		`<w:pStylew:val="BodyTextIndent"></w:pStyle></w:pPr><w:r><w:txml:space="preserve">เยื้อง</w:t>`,
+		`<w:pStylew:val="SceneBreak"></w:pStyle><w:spacingw:before="240"></w:spacing><w:jcw:val="center"></w:jc></w:pPr><w:r><w:txml:space="preserve">***</w:t>`,
+		// selector ที่มี tag ไม่ตรงกับ <div>
+		`<w:pStylew:val="BodyText"></w:pStyle></w:pPr><w:r><w:txml:space="preserve">div</w:t>`,
+		`<w:rStylew:val="SystemMessage"></w:rStyle></w:rPr><w:txml:space="preserve">เลเวลอัป</w:t>`,
+		// style attribute มีผลกว่า css ของ rule
+		`<w:rPr><w:i></w:i><w:iCs></w:iCs><w:colorw:val="0000FF"></w:color></w:rPr><w:txml:space="preserve">หมายเหตุ</w:t>`,
+		// rule ที่กำหนดเองแทน DefaultStyleRules
+		`<w:pStylew:val="BodyText"></w:pStyle></w:pPr><w:r><w:txml:space="preserve">ไม่มีrule</w:t>`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("document.xml missing %s", want)
+		}
+	}
+
+	styles := files["word/styles.xml"]
+	for _, want := range []string{
+		`<w:style w:type="paragraph" w:styleId="SceneBreak" w:customStyle="1">`,
+		`<w:style w:type="character" w:styleId="SystemMessage" w:customStyle="1">`,
+	} {
+		if !strings.Contains(styles, want) {
+			t.Errorf("styles.xml missing %s", want)
+		}
+	}
+	if strings.Count(styles, `w:styleId="BodyTextIndent"`) != 1 {
+		t.Errorf("built-in style BodyTextIndent should not be duplicated")
+	}
+}
+
+func TestDefaultStyleRules(t *testing.T) {
+	props := New(Options{}).bodyParagraphProps(parseFragment(t, `<div class="note indent-a">x</div>`)[0])
+	if props.PStyle.Val != "BodyTextIndent" {
+		t.Errorf("indent-a should use BodyTextIndent by default, got %s", props.PStyle.Val)
+	}
+	props = New(Options{StyleRules: []StyleRule{}}).bodyParagraphProps(parseFragment(t, `<p class="indent-a">x</p>`)[0])
+	if props.PStyle.Val != "BodyText" {
+		t.Errorf("empty StyleRules should disable indent-a, got %s", props.PStyle.Val)
+	}
+}
+
+func TestParseStyleRulesErrors(t *testing.T) {
+	for _, data := range []string{
+		`{"rules": [{"selector": "div > p", "paragraphStyle": "X"}]}`,
+		`{"rules": [{"selector": ".a", "paragraphStyle": "Bad\"Id"}]}`,
+		`{"rules": [{"selector": ".a"}]}`,
+		`{"rules": [{"selector": ".a", "style": "X"}]}`,
+		`[`,
+	} {
+		if _, err := ParseStyleRules([]byte(data)); err == nil {
+			t.Errorf("ParseStyleRules(%s) should fail", data)
+		}
+	}
+
+	err := Export(context.Background(), nil, &bytes.Buffer{}, Options{StyleRules: []StyleRule{{Selector: "#id", ParagraphStyle: "X"}}})
+	if err == nil || !strings.Contains(err.Error(), `style rule "#id"`) {
+		t.Errorf("Export should reject invalid selector, got %v", err)
+	}
+}
@@ -0,0 +1,438 @@
+package exportdocx
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// tableRef คือ <table> ที่แยกเป็นแถวและเซลล์ตาม grid แล้ว (ยังไม่ดาวน์โหลดรูปในเซลล์)
+type tableRef struct {
+	Node    *html.Node
+	Caption *html.Node
+	Rows    []tableRowRef
+	Columns int
+}
+
+type tableRowRef struct {
+	Header bool // แถวหัวตาราง (<thead> หรือแถวบนสุดที่มีแต่ <th>)
+	Cells  []tableCellRef
+}
+
+// tableCellRef คือเซลล์หนึ่งใน grid
+// เซลล์ที่ต่อจาก rowspan ของแถวบนมี VMerge "continue" และใช้ Node ของเซลล์แรก
+type tableCellRef struct {
+	Node     *html.Node // nil = เซลล์ว่างที่เติมให้แถวครบ grid
+	Header   bool
+	Col      int
+	ColSpan  int
+	VMerge   string // "restart", "continue" หรือ ""
+	Segments []contentSegment
+}
+
+// เส้นขอบเริ่มต้นของตาราง (style TableGrid มีเส้นขอบทุกด้าน)
+const tableStyle = "TableGrid"
+
+// ระยะขอบซ้ายและขวาในเซลล์ตาม style TableNormal (twips)
+const tableCellMargin = 108
+
+func isTableElement(n *html.Node) bool {
+	return n.Type == html.ElementNode && n.DataAtom == atom.Table
+}
+
+// parseTable แยก <table> เป็นแถวและเซลล์ พร้อมคำนวณตำแหน่งของ colspan และ rowspan
+func (e *Exporter) parseTable(n *html.Node) *tableRef {
+	table := &tableRef{Node: n}
+
+	// แถวจาก <thead>, <tbody>, <tfoot> หรือ <tr> ที่อยู่ใน <table> โดยตรง
+	type sourceRow struct {
+		Node *html.Node
+		Head bool
+	}
+	var rows []sourceRow
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		switch c.DataAtom {
+		case atom.Caption:
+			if table.Caption == nil {
+				table.Caption = c
+			}
+		case atom.Tr:
+			rows = append(rows, sourceRow{Node: c})
+		case atom.Thead, atom.Tbody, atom.Tfoot:
+			for tr := c.FirstChild; tr != nil; tr = tr.NextSibling {
+				if tr.Type == html.ElementNode && tr.DataAtom == atom.Tr {
+					rows = append(rows, sourceRow{Node: tr, Head: c.DataAtom == atom.Thead})
+				}
+			}
+		}
+	}
+
+	// rowspan ที่ยังค้างอยู่ในแต่ละคอลัมน์
+	type spanning struct {
+		Cell      tableCellRef
+		Remaining int
+	}
+	pending := make(map[int]*spanning)
+	header := true
+
+	for i, row := range rows {
+		var cells []tableCellRef
+		col := 0
+		// เติมเซลล์ต่อของ rowspan ที่ตำแหน่ง col
+		continueSpans := func() {
+			for span, ok := pending[col]; ok; span, ok = pending[col] {
+				cell := span.Cell
+				cell.VMerge = "continue"
+				cell.Segments = nil
+				cells = append(cells, cell)
+				if span.Remaining--; span.Remaining == 0 {
+					delete(pending, col)
+				}
+				col += cell.ColSpan
+			}
+		}
+
+		allHeaderCells := true
+		for c := row.Node.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode || (c.DataAtom != atom.Td && c.DataAtom != atom.Th) {
+				continue
+			}
+			continueSpans()
+
+			cell := tableCellRef{
+				Node:     c,
+				Header:   c.DataAtom == atom.Th,
+				Col:      col,
+				ColSpan:  spanAttr(c, "colspan", 1),
+				Segments: e.cellSegments(c),
+			}
+			// colspan ที่ทับคอลัมน์ของ rowspan จากแถวบนถูกตัดให้จบก่อนคอลัมน์นั้น
+			for span := 1; span < cell.ColSpan; span++ {
+				if _, ok := pending[col+span]; ok {
+					cell.ColSpan = span
+					break
+				}
+			}
+			allHeaderCells = allHeaderCells && cell.Header
+
+			// rowspan="0" คือถึงแถวสุดท้ายของตาราง
+			rowSpan := spanAttr(c, "rowspan", 0)
+			if rowSpan == 0 || rowSpan > len(rows)-i {
+				rowSpan = len(rows) - i
+			}
+			if rowSpan > 1 {
+				cell.VMerge = "restart"
+				pending[col] = &spanning{Cell: cell, Remaining: rowSpan - 1}
+			}
+			cells = append(cells, cell)
+			col += cell.ColSpan
+		}
+		continueSpans()
+		// แถวที่สั้นกว่า rowspan ที่ค้างอยู่ เติมเซลล์ว่างจนถึงคอลัมน์ของ rowspan
+		for len(pending) > 0 {
+			next := -1
+			for spanCol := range pending {
+				if spanCol >= col && (next < 0 || spanCol < next) {
+					next = spanCol
+				}
+			}
+			if next < 0 {
+				break
+			}
+			for ; col < next; col++ {
+				cells = append(cells, tableCellRef{Col: col, ColSpan: 1})
+			}
+			continueSpans()
+		}
+
+		// แถวหัวตารางต้องอยู่ติดกันที่ด้านบนของตาราง
+		header = header && len(cells) > 0 && (row.Head || allHeaderCells)
+		table.Rows = append(table.Rows, tableRowRef{Header: header, Cells: cells})
+		table.Columns = max(table.Columns, col)
+	}
+
+	// เติมเซลล์ว่างให้ทุกแถวครบจำนวนคอลัมน์
+	for i := range table.Rows {
+		row := &table.Rows[i]
+		col := 0
+		if len(row.Cells) > 0 {
+			last := row.Cells[len(row.Cells)-1]
+			col = last.Col + last.ColSpan
+		}
+		for ; col < table.Columns; col++ {
+			row.Cells = append(row.Cells, tableCellRef{Col: col, ColSpan: 1})
+		}
+	}
+	return table
+}
+
+// spanAttr อ่าน colspan หรือ rowspan (ค่าที่ไม่ถูกต้องใช้ fallback)
+func spanAttr(n *html.Node, key string, fallback int) int {
+	v, err := strconv.Atoi(strings.TrimSpace(getAttr(n, key)))
+	if err != nil || v < 0 || (v == 0 && key == "colspan") {
+		return max(fallback, 1)
+	}
+	// ค่าสูงสุดตาม HTML spec
+	return min(v, 1000)
+}
+
+// cellSegments แยกเนื้อหาของ <td>/<th>
+// เซลล์ที่มีแค่ข้อความเป็น paragraph เดียวที่สืบทอด style ของเซลล์
+func (e *Exporter) cellSegments(cell *html.Node) []contentSegment {
+	if !hasBlockChild(cell) && findSoleImage(childNodes(cell)) == nil {
+		return []contentSegment{{Type: "text", Node: cell}}
+	}
+	return e.parseContentWithFigures(cell)
+}
+
+// createTable แปลง tableRef เป็น caption (ถ้ามี) และ w:tbl
+func (e *Exporter) createTable(ctx context.Context, table *tableRef) []interface{} {
+	var content []interface{}
+	if table.Caption != nil && !isEmptyOrOnlyNbsp(textContent(table.Caption)) {
+		caption := Paragraph{
+			Props: &PPr{PStyle: &PStyle{Val: "Caption"}, Jc: &Jc{Val: "center"}},
+			Runs:  e.parseContentToRuns(childNodes(table.Caption), e.baseRunStyle().inherit(table.Caption)),
+		}
+		e.bookmarkAnchors(&caption, []*html.Node{table.Caption})
+		content = append(content, caption)
+	}
+	if table.Columns == 0 {
+		return content
+	}
+
+	widths, fixed := e.tableColumnWidths(table)
+	tbl := Table{
+		Props: TblPr{
+			TblStyle: &TblStyle{Val: tableStyle},
+			TblW:     &TblWidth{W: strconv.Itoa(sum(widths)), Type: "dxa"},
+		},
+	}
+	if fixed {
+		tbl.Props.TblLayout = &TblLayout{Type: "fixed"}
+	}
+	if tableWithoutBorders(table.Node, e.typography().FontSize) {
+		none := &Border{Val: "none", Sz: "0", Space: "0", Color: "auto"}
+		tbl.Props.TblBorders = &Borders{Top: none, Left: none, Bottom: none, Right: none, InsideH: none, InsideV: none}
+	}
+	for _, w := range widths {
+		tbl.Grid.Cols = append(tbl.Grid.Cols, GridCol{W: strconv.Itoa(w)})
+	}
+
+	for _, row := range table.Rows {
+		tr := TableRow{}
+		if row.Header {
+			tr.Props = &TrPr{CantSplit: &CantSplit{}, TblHeader: &TblHeader{}}
+		}
+		for _, cell := range row.Cells {
+			tr.Cells = append(tr.Cells, e.createTableCell(ctx, cell, sum(widths[cell.Col:cell.Col+cell.ColSpan])))
+		}
+		tbl.Rows = append(tbl.Rows, tr)
+	}
+	return append(content, tbl)
+}
+
+// createTableCell สร้าง w:tc พร้อมความกว้าง การรวมเซลล์ เส้นขอบ และเนื้อหา
+func (e *Exporter) createTableCell(ctx context.Context, cell tableCellRef, width int) TableCell {
+	tc := TableCell{
+		Props: TcPr{TcW: &TblWidth{W: strconv.Itoa(width), Type: "dxa"}},
+	}
+	if cell.ColSpan > 1 {
+		tc.Props.GridSpan = &GridSpan{Val: strconv.Itoa(cell.ColSpan)}
+	}
+	switch cell.VMerge {
+	case "restart":
+		tc.Props.VMerge = &VMerge{Val: "restart"}
+	case "continue":
+		tc.Props.VMerge = &VMerge{}
+	}
+	if cell.Node != nil {
+		tc.Props.TcBorders = cellBorders(getAttr(cell.Node, "style"), e.typography().FontSize)
+	}
+
+	// ตารางหรือรูปที่อยู่ในเซลล์ใช้ความกว้างของเซลล์ (หักระยะขอบในเซลล์ของ TableNormal)
+	outer := e.cellWidth
+	e.cellWidth = max(width-2*tableCellMargin, twipsPerPx)
+	defer func() { e.cellWidth = outer }()
+
+	// ย่อหน้าเนื้อหาในเซลล์ใช้ TableText (หรือ TableHeading ใน <th>) แทน BodyText
+	paraStyle := "TableText"
+	if cell.Header {
+		paraStyle = "TableHeading"
+	}
+	for _, item := range e.segmentsToParagraphs(ctx, cell.Segments) {
+		if para, ok := item.(Paragraph); ok {
+			restyleBodyParagraph(&para, paraStyle)
+			item = para
+		}
+		tc.Content = append(tc.Content, item)
+	}
+
+	// w:tc ต้องมีอย่างน้อยหนึ่ง paragraph และ element สุดท้ายต้องเป็น paragraph
+	if len(tc.Content) == 0 {
+		tc.Content = append(tc.Content, Paragraph{Props: &PPr{PStyle: &PStyle{Val: paraStyle}}})
+	} else if _, ok := tc.Content[len(tc.Content)-1].(Paragraph); !ok {
+		tc.Content = append(tc.Content, Paragraph{Props: &PPr{PStyle: &PStyle{Val: paraStyle}}})
+	}
+	return tc
+}
+
+// tableColumnWidths คำนวณความกว้างของแต่ละคอลัมน์ (twips)
+// ใช้ width ของเซลล์ที่ไม่ได้ colspan ส่วนคอลัมน์ที่ไม่ได้กำหนดแบ่งพื้นที่ที่เหลือเท่าๆ กัน
+// fixed = true ถ้า HTML กำหนดความกว้างไว้
+func (e *Exporter) tableColumnWidths(table *tableRef) (widths []int, fixed bool) {
+	available := e.contentWidthPx()
+	total := e.contentWidthTwips()
+	fontSize := e.typography().FontSize
+	if w, ok := htmlWidth(table.Node, available, fontSize); ok {
+		total = min(w, available) * twipsPerPx
+		fixed = true
+	}
+
+	widths = make([]int, table.Columns)
+	for _, row := range table.Rows {
+		for _, cell := range row.Cells {
+			if cell.Node == nil || cell.ColSpan != 1 || cell.VMerge == "continue" || widths[cell.Col] > 0 {
+				continue
+			}
+			if w, ok := htmlWidth(cell.Node, total/twipsPerPx, fontSize); ok && w > 0 {
+				widths[cell.Col] = w * twipsPerPx
+				fixed = true
+			}
+		}
+	}
+
+	var unknown int
+	for _, w := range widths {
+		if w == 0 {
+			unknown++
+		}
+	}
+	if unknown > 0 {
+		// คอลัมน์ต้องกว้างอย่างน้อย 0.25 นิ้ว
+		each := max((total-sum(widths))/unknown, 360)
+		for i := range widths {
+			if widths[i] == 0 {
+				widths[i] = each
+			}
+		}
+	}
+	return widths, fixed
+}
+
+// htmlWidth อ่านความกว้าง (px) จาก style หรือ width attribute (% คิดจาก relativeTo, em คิดจาก fontSize)
+func htmlWidth(n *html.Node, relativeTo, fontSize int) (int, bool) {
+	if w, ok := cssLength(getAttr(n, "style"), "width", relativeTo, fontSize); ok {
+		return w, true
+	}
+	attr := strings.TrimSpace(getAttr(n, "width"))
+	if pct, ok := strings.CutSuffix(attr, "%"); ok {
+		if v, err := strconv.ParseFloat(pct, 64); err == nil {
+			return int(float64(relativeTo)*v/100 + 0.5), true
+		}
+		return 0, false
+	}
+	if v, err := strconv.Atoi(strings.TrimSuffix(attr, "px")); err == nil && v > 0 {
+		return v, true
+	}
+	return 0, false
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+// tableWithoutBorders คืน true ถ้า <table border="0"> หรือ style border: none
+func tableWithoutBorders(n *html.Node, fontSize int) bool {
+	if strings.TrimSpace(getAttr(n, "border")) == "0" {
+		return true
+	}
+	border, ok := cssBorder(getAttr(n, "style"), "border", fontSize)
+	return ok && border == nil
+}
+
+// รูปแบบเส้นของ CSS -> w:val
+var borderStyles = map[string]string{
+	"solid":  "single",
+	"dashed": "dashed",
+	"dotted": "dotted",
+	"double": "double",
+}
+
+// ความหนาของเส้นแบบคำ (px)
+var borderWidths = map[string]string{
+	"thin":   "1px",
+	"medium": "3px",
+	"thick":  "5px",
+}
+
+// cellBorders แปลง border, border-top, border-right, border-bottom และ border-left ของเซลล์เป็น w:tcBorders
+func cellBorders(style string, fontSize int) *Borders {
+	borders := &Borders{}
+	set := false
+	for _, side := range []struct {
+		property string
+		targets  []**Border
+	}{
+		{"border", []**Border{&borders.Top, &borders.Left, &borders.Bottom, &borders.Right}},
+		{"border-top", []**Border{&borders.Top}},
+		{"border-right", []**Border{&borders.Right}},
+		{"border-bottom", []**Border{&borders.Bottom}},
+		{"border-left", []**Border{&borders.Left}},
+	} {
+		border, ok := cssBorder(style, side.property, fontSize)
+		if !ok {
+			continue
+		}
+		if border == nil {
+			border = &Border{Val: "nil"}
+		}
+		for _, target := range side.targets {
+			*target = border
+		}
+		set = true
+	}
+	if !set {
+		return nil
+	}
+	return borders
+}
+
+// cssBorder อ่าน shorthand เช่น "1px solid #ccc" (ok = false ถ้าไม่มี property นี้)
+// border: none คืน nil พร้อม ok = true
+func cssBorder(style, property string, fontSize int) (*Border, bool) {
+	value, ok := cssProperty(style, property)
+	if !ok {
+		return nil, false
+	}
+	border := &Border{Val: "single", Sz: "4", Space: "0", Color: "auto"}
+	for _, token := range cssTokens(value) {
+		if width, ok := borderWidths[token]; ok {
+			token = width
+		}
+		if token == "none" || token == "hidden" {
+			return nil, true
+		}
+		if val, ok := borderStyles[token]; ok {
+			border.Val = val
+		} else if twips, ok := cssTwips(token, fontSize); ok {
+			if twips <= 0 {
+				return nil, true
+			}
+			// w:sz เป็น 1/8 pt (20 twips = 1 pt) ในช่วง 2-96
+			border.Sz = strconv.Itoa(min(max((twips*8+10)/20, 2), 96))
+		} else if color, ok := cssColor(token); ok {
+			border.Color = color
+		}
+	}
+	return border, true
+}
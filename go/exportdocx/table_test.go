@@ -0,0 +1,124 @@
+package exportdocx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+func TestExportTables(t *testing.T) {
+	chapters := []ChapterData{{ID: "1", Chapter: "บทที่ 1", Body: `<table style="width: 400px"><caption>หน้าต่างสถานะ</caption>` +
+		`<thead><tr><th colspan="2">ชื่อ</th><th>ค่า</th></tr></thead><tbody>` +
+		`<tr><td rowspan="2" style="width: 100px; border: 2px dashed #c00">HP</td><td>ปัจจุบัน</td><td><b>100</b></td></tr>` +
+		`<tr><td>สูงสุด</td><td><p>200</p><p>(+10)</p></td></tr>` +
+		`<tr><td>MP</td><td colspan="2"><table border="0"><tr><td>a</td><td>b</td></tr></table></td></tr>` +
+		`<tr><td>EXP</td></tr>` +
+		`</tbody></table>`}}
+
+	var buf bytes.Buffer
+	if err := Export(context.Background(), chapters, &buf, Options{}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	files := readZipFiles(t, buf.Bytes())
+	doc := compactXML(files["word/document.xml"])
+
+	for _, want := range []string{
+		// caption อยู่ก่อนตาราง
+		`<w:pStylew:val="Caption"></w:pStyle><w:jcw:val="center"></w:jc></w:pPr><w:r><w:txml:space="preserve">หน้าต่างสถานะ</w:t></w:r></w:p><w:tbl>`,
+		// ความกว้างจาก style: ตาราง 400px และคอลัมน์แรก 100px ส่วนที่เหลือแบ่งเท่ากัน
+		`<w:tblStylew:val="TableGrid"></w:tblStyle><w:tblWw:w="6000"w:type="dxa"></w:tblW><w:tblLayoutw:type="fixed"></w:tblLayout>`,
+		`<w:tblGrid><w:gridColw:w="1500"></w:gridCol><w:gridColw:w="2250"></w:gridCol><w:gridColw:w="2250"></w:gridCol></w:tblGrid>`,
+		// แถวหัวตารางและ colspan
+		`<w:trPr><w:cantSplit></w:cantSplit><w:tblHeader></w:tblHeader></w:trPr><w:tc><w:tcPr><w:tcWw:w="3750"w:type="dxa"></w:tcW><w:gridSpanw:val="2"></w:gridSpan></w:tcPr><w:p><w:pPr><w:pStylew:val="TableHeading"></w:pStyle>`,
+		// rowspan และเส้นขอบของเซลล์
+		`<w:vMergew:val="restart"></w:vMerge><w:tcBorders><w:topw:val="dashed"w:sz="12"w:space="0"w:color="CC0000"></w:top>`,
+		`<w:vMerge></w:vMerge>`,
+		// formatting และหลาย paragraph ในเซลล์
+		`<w:pStylew:val="TableText"></w:pStyle></w:pPr><w:r><w:rPr><w:rStylew:val="Strong"></w:rStyle></w:rPr><w:txml:space="preserve">100</w:t>`,
+		`<w:txml:space="preserve">200</w:t></w:r></w:p><w:p><w:pPr><w:pStylew:val="TableText"></w:pStyle></w:pPr><w:r><w:txml:space="preserve">(+10)</w:t>`,
+		// ตารางซ้อนกว้างเท่าเซลล์และต้องมี paragraph ปิดท้ายเซลล์
+		`<w:tblWw:w="4284"w:type="dxa"></w:tblW><w:tblBorders><w:topw:val="none"`,
+		`</w:tbl><w:p><w:pPr><w:pStylew:val="TableText"></w:pStyle></w:pPr></w:p></w:tc>`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("document.xml missing %s", want)
+		}
+	}
+
+	// แถวที่เซลล์ไม่ครบถูกเติมเซลล์ว่าง
+	rows := strings.Split(doc, "<w:tr>")
+	if last := rows[len(rows)-1]; strings.Count(last, "<w:tc>") != 3 {
+		t.Errorf("short row should be padded to 3 cells: %s", last)
+	}
+	if got := strings.Count(doc, "<w:tblHeader>"); got != 1 {
+		t.Errorf("got %d header rows, want 1", got)
+	}
+
+	styles := files["word/styles.xml"]
+	for _, id := range []string{"TableNormal", "TableGrid", "TableText", "TableHeading"} {
+		if !strings.Contains(styles, `w:styleId="`+id+`"`) {
+			t.Errorf("styles.xml missing %s style", id)
+		}
+	}
+}
+
+func TestParseTableOverlappingSpans(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(`<table>` +
+		`<tr><td>a</td><td rowspan="3">B</td><td>c</td><td rowspan="2">D</td></tr>` +
+		`<tr><td colspan="3">wide</td></tr>` +
+		`<tr><td>x</td></tr>` +
+		`<tr><td>y</td><td>z</td><td>w</td><td>v</td></tr>` +
+		`</table>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := New(Options{}).parseTable(findElement(doc, atom.Table))
+
+	// แต่ละเซลล์คือ คอลัมน์:colspan:vMerge (เซลล์ต่อของ rowspan ต้องอยู่ในแถวที่ถูกต้องเสมอ)
+	want := []string{
+		"0:1:, 1:1:restart, 2:1:, 3:1:restart",
+		"0:1:, 1:1:continue, 2:1:, 3:1:continue",
+		"0:1:, 1:1:continue, 2:1:, 3:1:",
+		"0:1:, 1:1:, 2:1:, 3:1:",
+	}
+	if len(table.Rows) != len(want) || table.Columns != 4 {
+		t.Fatalf("got %d rows, %d columns, want %d rows, 4 columns", len(table.Rows), table.Columns, len(want))
+	}
+	for i, row := range table.Rows {
+		var cells []string
+		for _, cell := range row.Cells {
+			cells = append(cells, fmt.Sprintf("%d:%d:%s", cell.Col, cell.ColSpan, cell.VMerge))
+		}
+		if got := strings.Join(cells, ", "); got != want[i] {
+			t.Errorf("row %d: got %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestCSSBorder(t *testing.T) {
+	tests := []struct {
+		style string
+		want  *Border
+		ok    bool
+	}{
+		{"border: 1px solid #ccc", &Border{Val: "single", Sz: "6", Space: "0", Color: "CCCCCC"}, true},
+		{"border: double 3pt", &Border{Val: "double", Sz: "24", Space: "0", Color: "auto"}, true},
+		{"border: thin dotted rgb(255 0 0 / 50%)", &Border{Val: "dotted", Sz: "6", Space: "0", Color: "FF8080"}, true},
+		{"border: 0.1em solid hsl(120, 100%, 25%)", &Border{Val: "single", Sz: "9", Space: "0", Color: "008000"}, true},
+		{"border: 1px solid red; border: 2pt dashed", &Border{Val: "dashed", Sz: "16", Space: "0", Color: "auto"}, true},
+		{"border: none", nil, true},
+		{"border: 0", nil, true},
+		{"color: red", nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := cssBorder(tt.style, "border", 22)
+		if ok != tt.ok || (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("cssBorder(%q) = %+v, %v; want %+v, %v", tt.style, got, ok, tt.want, tt.ok)
+		}
+	}
+}
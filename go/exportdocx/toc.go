@@ -0,0 +1,111 @@
+package exportdocx
+
+import "strconv"
+
+// instruction ของ TOC field: หัวข้อระดับ 1-3, ลิงก์ไปหัวข้อ, ซ่อนเลขหน้าใน web view, ใช้ outline level
+const tocInstruction = ` TOC \o "1-3" \h \z \u `
+
+// bookmark สร้าง bookmarkStart/bookmarkEnd คู่หนึ่งที่มี ID ไม่ซ้ำกันในเอกสาร
+func (e *Exporter) bookmark(name string) (*BookmarkStart, *BookmarkEnd) {
+	id := strconv.Itoa(e.bookmarkID)
+	e.bookmarkID++
+	return &BookmarkStart{Id: id, Name: name}, &BookmarkEnd{Id: id}
+}
+
+// pageBreakParagraph คือ paragraph ที่มีแค่ page break
+func pageBreakParagraph() Paragraph {
+	return Paragraph{
+		Runs: []Run{{Break: &Break{Type: "page"}}},
+	}
+}
+
+// titlePageParagraphs สร้างหน้าปกจาก Title และ Author (ค่าที่ว่างไม่ใส่ย่อหน้า)
+func (e *Exporter) titlePageParagraphs() []Paragraph {
+	var paragraphs []Paragraph
+	if e.opts.Title != "" {
+		paragraphs = append(paragraphs, Paragraph{
+			Props: &PPr{PStyle: &PStyle{Val: "Title"}},
+			Runs:  []Run{{Text: &Text{Value: e.opts.Title, Space: "preserve"}}},
+		})
+	}
+	if e.opts.Author != "" {
+		paragraphs = append(paragraphs, Paragraph{
+			Props: &PPr{PStyle: &PStyle{Val: "Subtitle"}},
+			Runs:  []Run{{Text: &Text{Value: e.opts.Author, Space: "preserve"}}},
+		})
+	}
+	return paragraphs
+}
+
+// tocParagraphs สร้างสารบัญเป็น TOC field ที่มีรายการบทใส่ไว้แล้ว
+// รายการแต่ละบทเป็นลิงก์ไปยัง bookmark ของหัวข้อบท จึงใช้ได้แม้ในโปรแกรมที่ไม่อัปเดต field
+// เลขหน้าเป็น PAGEREF ที่ Word คำนวณเมื่ออัปเดต field (settings.xml มี updateFields)
+// ผลที่เก็บไว้ของ PAGEREF คือเลขหน้าที่รู้ได้ก่อน (tocPageNumber) สำหรับโปรแกรมที่ไม่อัปเดต field
+func (e *Exporter) tocParagraphs(chapters []ChapterData, bookmarks []string) []Paragraph {
+	tocTitle := e.opts.TOCTitle
+	if tocTitle == "" {
+		tocTitle = "สารบัญ"
+	}
+
+	paragraphs := []Paragraph{{
+		Props: &PPr{PStyle: &PStyle{Val: "TOCHeading"}},
+		Runs:  []Run{{Text: &Text{Value: tocTitle, Space: "preserve"}}},
+	}}
+
+	// ตำแหน่ง tab ของเลขหน้า ชิดขวาของพื้นที่ข้อความ
+	tabs := &Tabs{Tabs: []TabStop{{
+		Val:    "right",
+		Leader: "dot",
+		Pos:    strconv.Itoa(e.textWidthTwips()),
+	}}}
+
+	for i, chapter := range chapters {
+		entry := Paragraph{
+			Props: &PPr{PStyle: &PStyle{Val: "TOC1"}, Tabs: tabs},
+			Hyperlink: &Hyperlink{
+				Anchor:  bookmarks[i],
+				History: "1",
+				Runs: []Run{
+					{Text: &Text{Value: chapter.Chapter, Space: "preserve"}},
+					{Tab: &Tab{}},
+					{FldChar: &FldChar{FldCharType: "begin"}},
+					{InstrText: &InstrText{Value: " PAGEREF " + bookmarks[i] + ` \h `, Space: "preserve"}},
+					{FldChar: &FldChar{FldCharType: "separate"}},
+					{Text: &Text{Value: e.tocPageNumber(i), Space: "preserve"}},
+					{FldChar: &FldChar{FldCharType: "end"}},
+				},
+			},
+		}
+
+		// รายการแรกเปิด TOC field และใส่ separate ก่อนผลลัพธ์ (รายการบท)
+		if i == 0 {
+			entry.Runs = []Run{
+				{FldChar: &FldChar{FldCharType: "begin", Dirty: "true"}},
+				{InstrText: &InstrText{Value: tocInstruction, Space: "preserve"}},
+				{FldChar: &FldChar{FldCharType: "separate"}},
+			}
+		}
+		paragraphs = append(paragraphs, entry)
+	}
+
+	// ปิด TOC field ใน paragraph ของตัวเอง (แบบเดียวกับที่ Word สร้าง)
+	end := Paragraph{Runs: []Run{{FldChar: &FldChar{FldCharType: "end"}}}}
+	if len(chapters) == 0 {
+		end.Runs = []Run{
+			{FldChar: &FldChar{FldCharType: "begin", Dirty: "true"}},
+			{InstrText: &InstrText{Value: tocInstruction, Space: "preserve"}},
+			{FldChar: &FldChar{FldCharType: "separate"}},
+			{FldChar: &FldChar{FldCharType: "end"}},
+		}
+	}
+	return append(paragraphs, end)
+}
+
+// tocPageNumber คืนเลขหน้าของบทที่ index ก่อนอัปเดต field
+// เลขหน้ารู้ได้เฉพาะบทที่เริ่มนับหน้า 1 ใหม่ (section ต่อบท) บทอื่นใช้ "-" จนกว่า Word จะอัปเดต field
+func (e *Exporter) tocPageNumber(index int) string {
+	if e.sectionPerChapter() && (index == 0 || e.opts.RestartPageNumbers) {
+		return "1"
+	}
+	return "-"
+}
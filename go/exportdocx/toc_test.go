@@ -0,0 +1,99 @@
+package exportdocx
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestExportTitlePageAndTOC(t *testing.T) {
+	chapters := []ChapterData{
+		{ID: "1", Chapter: "บทที่ 1", Body: "<p>หนึ่ง</p>"},
+		{ID: "2", Chapter: "บทที่ 2 & ต่อ", Body: "<p>สอง</p>"},
+	}
+	opts := Options{Title: "นิยาย <ทดสอบ>", Author: "ผู้เขียน", TitlePage: true, TOC: true}
+
+	var buf bytes.Buffer
+	if err := Export(context.Background(), chapters, &buf, opts); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	files := readZipFiles(t, buf.Bytes())
+	doc := files["word/document.xml"]
+
+	// ลำดับ: หน้าปก -> สารบัญ -> บทแรก
+	order := []string{
+		"นิยาย &lt;ทดสอบ&gt;",
+		"ผู้เขียน",
+		`<w:pStyle w:val="TOCHeading">`,
+		`TOC \o &#34;1-3&#34; \h \z \u`,
+		`<w:hyperlink w:anchor="_TocChapter1" w:history="1">`,
+		`<w:hyperlink w:anchor="_TocChapter2" w:history="1">`,
+		`<w:bookmarkStart w:id="0" w:name="_TocChapter1">`,
+		`<w:bookmarkStart w:id="1" w:name="_TocChapter2">`,
+	}
+	pos := 0
+	for _, want := range order {
+		i := strings.Index(doc[pos:], want)
+		if i < 0 {
+			t.Fatalf("document.xml missing %q after offset %d", want, pos)
+		}
+		pos += i + len(want)
+	}
+
+	if got := strings.Count(doc, `w:fldCharType="begin"`); got != strings.Count(doc, `w:fldCharType="end"`) || got != 3 {
+		t.Errorf("unbalanced fields: %d begin, %d end", got, strings.Count(doc, `w:fldCharType="end"`))
+	}
+	// PAGEREF มีผลที่เก็บไว้สำหรับโปรแกรมที่ไม่อัปเดต field
+	pageRef := `PAGEREF_TocChapter2\h</w:instrText></w:r><w:r><w:fldCharw:fldCharType="separate"></w:fldChar></w:r><w:r><w:txml:space="preserve">-</w:t></w:r>`
+	if !strings.Contains(compactXML(doc), pageRef) {
+		t.Errorf("PAGEREF of chapter 2 has no cached result")
+	}
+	if !strings.Contains(files["word/settings.xml"], `<w:updateFields w:val="true"/>`) {
+		t.Error("settings.xml should enable updateFields when TOC is on")
+	}
+	if !strings.Contains(files["word/_rels/document.xml.rels"], `Target="settings.xml"`) {
+		t.Error("document.xml.rels missing settings relationship")
+	}
+	if !strings.Contains(files["docProps/core.xml"], "<dc:title>นิยาย &lt;ทดสอบ&gt;</dc:title>") {
+		t.Errorf("core.xml title not set:\n%s", files["docProps/core.xml"])
+	}
+}
+
+func TestExportWithoutTOCDoesNotUpdateFields(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(context.Background(), []ChapterData{{ID: "1", Chapter: "1", Body: "<p>x</p>"}}, &buf, Options{}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	files := readZipFiles(t, buf.Bytes())
+	if strings.Contains(files["word/settings.xml"], "updateFields") {
+		t.Error("updateFields should only be set when TOC is on")
+	}
+	if strings.Contains(files["word/document.xml"], "TOC ") {
+		t.Error("TOC field written without the TOC option")
+	}
+}
+
+func TestTitlePageWithoutTitle(t *testing.T) {
+	chapters := []ChapterData{{ID: "1", Chapter: "บทที่ 1", Body: "<p>หนึ่ง</p>"}}
+	for _, opts := range []Options{
+		{Author: "ผู้เขียน", TitlePage: true},
+		{TitlePage: true},
+	} {
+		var buf bytes.Buffer
+		if err := Export(context.Background(), chapters, &buf, opts); err != nil {
+			t.Fatalf("Export: %v", err)
+		}
+		doc := readZipFiles(t, buf.Bytes())["word/document.xml"]
+		if strings.Contains(doc, `<w:pStyle w:val="Title">`) {
+			t.Errorf("author %q: empty Title paragraph written", opts.Author)
+		}
+		if got := strings.Contains(doc, `<w:pStyle w:val="Subtitle">`); got != (opts.Author != "") {
+			t.Errorf("author %q: Subtitle paragraph = %v", opts.Author, got)
+		}
+		// ไม่มีหน้าปกก็ไม่มี page break นำหน้าบทแรก
+		if opts.Author == "" && strings.Contains(doc, `w:type="page"`) {
+			t.Errorf("page break written without a title page")
+		}
+	}
+}
@@ -0,0 +1,649 @@
+package exportdocx
+
+import "encoding/xml"
+
+// ChapterData คือข้อมูลหนึ่งบท (หนึ่งแถวใน CSV)
+type ChapterData struct {
+	ID      string
+	Chapter string
+	Body    string
+}
+
+// DOCX XML Structures
+type Document struct {
+	XMLName  xml.Name `xml:"w:document"`
+	Xmlns    string   `xml:"xmlns:w,attr"`
+	XmlnsR   string   `xml:"xmlns:r,attr"`
+	XmlnsWP  string   `xml:"xmlns:wp,attr"`
+	XmlnsA   string   `xml:"xmlns:a,attr"`
+	XmlnsPic string   `xml:"xmlns:pic,attr"`
+	Body     Body     `xml:"w:body"`
+}
+
+type Body struct {
+	XMLName xml.Name      `xml:"w:body"`
+	Content []interface{} `xml:",any"`
+	SectPr  SectPr        `xml:"w:sectPr"`
+}
+
+// ลำดับ field ของ SectPr ต้องตรงกับลำดับใน schema ของ w:sectPr
+type SectPr struct {
+	XMLName    xml.Name                `xml:"w:sectPr"`
+	HeaderRefs []HeaderFooterReference `xml:"w:headerReference"`
+	FooterRefs []HeaderFooterReference `xml:"w:footerReference"`
+	Type       *SectType               `xml:"w:type,omitempty"`
+	PgSz       PgSz                    `xml:"w:pgSz"`
+	PgMar      PgMar                   `xml:"w:pgMar"`
+	PgNumType  *PgNumType              `xml:"w:pgNumType,omitempty"`
+	TitlePg    *TitlePg                `xml:"w:titlePg,omitempty"`
+}
+
+// SectType คือวิธีเริ่ม section (nextPage, oddPage, evenPage, continuous)
+type SectType struct {
+	XMLName xml.Name `xml:"w:type"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// PgNumType กำหนดเลขหน้าเริ่มต้นของ section
+type PgNumType struct {
+	XMLName xml.Name `xml:"w:pgNumType"`
+	Start   string   `xml:"w:start,attr,omitempty"`
+}
+
+// HeaderFooterReference ใช้ได้ทั้ง w:headerReference และ w:footerReference (ชื่อ element มาจาก tag ของ SectPr)
+type HeaderFooterReference struct {
+	Type  string `xml:"w:type,attr"`
+	RelId string `xml:"r:id,attr"`
+}
+
+// TitlePg ให้หน้าแรกของ section ใช้ header/footer แบบ "first"
+type TitlePg struct {
+	XMLName xml.Name `xml:"w:titlePg"`
+}
+
+type PgSz struct {
+	XMLName xml.Name `xml:"w:pgSz"`
+	W       string   `xml:"w:w,attr"`
+	H       string   `xml:"w:h,attr"`
+	Orient  string   `xml:"w:orient,attr,omitempty"`
+}
+
+type PgMar struct {
+	XMLName xml.Name `xml:"w:pgMar"`
+	Top     string   `xml:"w:top,attr"`
+	Right   string   `xml:"w:right,attr"`
+	Bottom  string   `xml:"w:bottom,attr"`
+	Left    string   `xml:"w:left,attr"`
+	Header  string   `xml:"w:header,attr,omitempty"`
+	Footer  string   `xml:"w:footer,attr,omitempty"`
+	Gutter  string   `xml:"w:gutter,attr,omitempty"`
+}
+
+type Paragraph struct {
+	XMLName       xml.Name       `xml:"w:p"`
+	Props         *PPr           `xml:"w:pPr,omitempty"`
+	BookmarkStart *BookmarkStart `xml:"w:bookmarkStart,omitempty"`
+	Runs          RunList        `xml:"w:r"`
+	Hyperlink     *Hyperlink     `xml:"w:hyperlink,omitempty"`
+	BookmarkEnd   *BookmarkEnd   `xml:"w:bookmarkEnd,omitempty"`
+}
+
+// ลำดับ field ของ PPr ต้องตรงกับลำดับใน schema ของ w:pPr
+type PPr struct {
+	XMLName    xml.Name    `xml:"w:pPr"`
+	PStyle     *PStyle     `xml:"w:pStyle,omitempty"`
+	NumPr      *NumPr      `xml:"w:numPr,omitempty"`
+	Tabs       *Tabs       `xml:"w:tabs,omitempty"`
+	Spacing    *Spacing    `xml:"w:spacing,omitempty"`
+	Ind        *Ind        `xml:"w:ind,omitempty"`
+	Jc         *Jc         `xml:"w:jc,omitempty"`
+	OutlineLvl *OutlineLvl `xml:"w:outlineLvl,omitempty"`
+	SectPr     *SectPr     `xml:"w:sectPr,omitempty"`
+}
+
+type Run struct {
+	XMLName   xml.Name   `xml:"w:r"`
+	Props     *RPr       `xml:"w:rPr,omitempty"`
+	FldChar   *FldChar   `xml:"w:fldChar,omitempty"`
+	InstrText *InstrText `xml:"w:instrText,omitempty"`
+	Tab       *Tab       `xml:"w:tab,omitempty"`
+	Text      *Text      `xml:"w:t,omitempty"`
+	Break     *Break     `xml:"w:br,omitempty"`
+	Drawing   *Drawing   `xml:"w:drawing,omitempty"`
+
+	// Hyperlink คือลิงก์ที่ครอบ run นี้ (ไม่ได้เขียนใน w:r แต่ RunList ใช้ครอบด้วย w:hyperlink)
+	Hyperlink *Hyperlink `xml:"-"`
+}
+
+// RunList คือ runs ของ paragraph ที่อาจอยู่ในลิงก์
+// run ที่ติดกันและมี Hyperlink เดียวกัน (pointer เดียวกัน) ถูกครอบด้วย w:hyperlink เดียว
+type RunList []Run
+
+func (runs RunList) MarshalXML(enc *xml.Encoder, _ xml.StartElement) error {
+	for i := 0; i < len(runs); {
+		link := runs[i].Hyperlink
+		if link == nil {
+			if err := enc.Encode(runs[i]); err != nil {
+				return err
+			}
+			i++
+			continue
+		}
+
+		j := i + 1
+		for j < len(runs) && runs[j].Hyperlink == link {
+			j++
+		}
+		if link.RelId == "" && link.Anchor == "" {
+			// ลิงก์ "#id" ที่หาเป้าหมายไม่พบ เขียนเป็น run ธรรมดาเหมือน href ที่ไม่รองรับ
+			for _, run := range runs[i:j] {
+				if err := enc.Encode(withoutLinkStyle(run)); err != nil {
+					return err
+				}
+			}
+			i = j
+			continue
+		}
+		group := *link
+		group.Runs = runs[i:j]
+		if err := enc.Encode(group); err != nil {
+			return err
+		}
+		i = j
+	}
+	return nil
+}
+
+// withoutLinkStyle ลบ character style Hyperlink ที่ใส่ไว้ตอนแปลง <a> ออกจาก run
+func withoutLinkStyle(run Run) Run {
+	if run.Props == nil || run.Props.RStyle == nil || run.Props.RStyle.Val != "Hyperlink" {
+		return run
+	}
+	props := *run.Props
+	props.RStyle = nil
+	run.Props = &props
+	if props == (RPr{}) {
+		run.Props = nil
+	}
+	return run
+}
+
+type Drawing struct {
+	XMLName xml.Name `xml:"w:drawing"`
+	Inline  *Inline  `xml:"wp:inline"`
+}
+
+type Inline struct {
+	XMLName           xml.Name          `xml:"wp:inline"`
+	DistT             string            `xml:"distT,attr"`
+	DistB             string            `xml:"distB,attr"`
+	DistL             string            `xml:"distL,attr"`
+	DistR             string            `xml:"distR,attr"`
+	Extent            Extent            `xml:"wp:extent"`
+	EffectExt         EffectExt         `xml:"wp:effectExtent"`
+	DocPr             DocPr             `xml:"wp:docPr"`
+	CNvGraphicFramePr CNvGraphicFramePr `xml:"wp:cNvGraphicFramePr"`
+	Graphic           Graphic           `xml:"a:graphic"`
+}
+
+type Extent struct {
+	XMLName xml.Name `xml:"wp:extent"`
+	Cx      string   `xml:"cx,attr"`
+	Cy      string   `xml:"cy,attr"`
+}
+
+type EffectExt struct {
+	XMLName xml.Name `xml:"wp:effectExtent"`
+	L       string   `xml:"l,attr"`
+	T       string   `xml:"t,attr"`
+	R       string   `xml:"r,attr"`
+	B       string   `xml:"b,attr"`
+}
+
+type DocPr struct {
+	XMLName xml.Name `xml:"wp:docPr"`
+	Id      string   `xml:"id,attr"`
+	Name    string   `xml:"name,attr"`
+}
+
+type CNvGraphicFramePr struct {
+	XMLName           xml.Name          `xml:"wp:cNvGraphicFramePr"`
+	GraphicFrameLocks GraphicFrameLocks `xml:"a:graphicFrameLocks"`
+}
+
+type GraphicFrameLocks struct {
+	XMLName        xml.Name `xml:"a:graphicFrameLocks"`
+	NoChangeAspect string   `xml:"noChangeAspect,attr"`
+}
+
+type Graphic struct {
+	XMLName     xml.Name    `xml:"a:graphic"`
+	GraphicData GraphicData `xml:"a:graphicData"`
+}
+
+type GraphicData struct {
+	XMLName xml.Name `xml:"a:graphicData"`
+	Uri     string   `xml:"uri,attr"`
+	Pic     Pic      `xml:"pic:pic"`
+}
+
+type Pic struct {
+	XMLName  xml.Name `xml:"pic:pic"`
+	NvPicPr  NvPicPr  `xml:"pic:nvPicPr"`
+	BlipFill BlipFill `xml:"pic:blipFill"`
+	SpPr     SpPr     `xml:"pic:spPr"`
+}
+
+type NvPicPr struct {
+	XMLName  xml.Name `xml:"pic:nvPicPr"`
+	CNvPr    CNvPr    `xml:"pic:cNvPr"`
+	CNvPicPr CNvPicPr `xml:"pic:cNvPicPr"`
+}
+
+type CNvPr struct {
+	XMLName xml.Name `xml:"pic:cNvPr"`
+	Id      string   `xml:"id,attr"`
+	Name    string   `xml:"name,attr"`
+}
+
+type CNvPicPr struct {
+	XMLName xml.Name `xml:"pic:cNvPicPr"`
+}
+
+type BlipFill struct {
+	XMLName xml.Name `xml:"pic:blipFill"`
+	Blip    Blip     `xml:"a:blip"`
+	Stretch Stretch  `xml:"a:stretch"`
+}
+
+type Blip struct {
+	XMLName xml.Name `xml:"a:blip"`
+	Embed   string   `xml:"r:embed,attr"`
+}
+
+type Stretch struct {
+	XMLName  xml.Name `xml:"a:stretch"`
+	FillRect FillRect `xml:"a:fillRect"`
+}
+
+type FillRect struct {
+	XMLName xml.Name `xml:"a:fillRect"`
+}
+
+type SpPr struct {
+	XMLName  xml.Name `xml:"pic:spPr"`
+	Xfrm     Xfrm     `xml:"a:xfrm"`
+	PrstGeom PrstGeom `xml:"a:prstGeom"`
+}
+
+type Xfrm struct {
+	XMLName xml.Name `xml:"a:xfrm"`
+	Off     Off      `xml:"a:off"`
+	Ext     Ext      `xml:"a:ext"`
+}
+
+type Off struct {
+	XMLName xml.Name `xml:"a:off"`
+	X       string   `xml:"x,attr"`
+	Y       string   `xml:"y,attr"`
+}
+
+type Ext struct {
+	XMLName xml.Name `xml:"a:ext"`
+	Cx      string   `xml:"cx,attr"`
+	Cy      string   `xml:"cy,attr"`
+}
+
+type PrstGeom struct {
+	XMLName xml.Name `xml:"a:prstGeom"`
+	Prst    string   `xml:"prst,attr"`
+	AvLst   AvLst    `xml:"a:avLst"`
+}
+
+type AvLst struct {
+	XMLName xml.Name `xml:"a:avLst"`
+}
+
+// ลำดับ field ของ RPr ต้องตรงกับลำดับใน schema ของ w:rPr
+type RPr struct {
+	XMLName   xml.Name   `xml:"w:rPr"`
+	RStyle    *RStyle    `xml:"w:rStyle,omitempty"`
+	Fonts     *RFonts    `xml:"w:rFonts,omitempty"`
+	Bold      *Bold      `xml:"w:b,omitempty"`
+	BoldCs    *BoldCs    `xml:"w:bCs,omitempty"`
+	Italic    *Italic    `xml:"w:i,omitempty"`
+	ItalicCs  *ItalicCs  `xml:"w:iCs,omitempty"`
+	Caps      *Caps      `xml:"w:caps,omitempty"`
+	SmallCaps *SmallCaps `xml:"w:smallCaps,omitempty"`
+	Strike    *Strike    `xml:"w:strike,omitempty"`
+	DStrike   *DStrike   `xml:"w:dstrike,omitempty"`
+	Color     *Color     `xml:"w:color,omitempty"`
+	Size      *Size      `xml:"w:sz,omitempty"`
+	SizeCs    *SizeCs    `xml:"w:szCs,omitempty"`
+	Highlight *Highlight `xml:"w:highlight,omitempty"`
+	Underline *Underline `xml:"w:u,omitempty"`
+	Shd       *Shd       `xml:"w:shd,omitempty"`
+	VertAlign *VertAlign `xml:"w:vertAlign,omitempty"`
+}
+
+// RFonts กำหนดฟอนต์ของอักษรละติน (ข้อความไทยใช้ฟอนต์ complex script ตาม style)
+type RFonts struct {
+	XMLName xml.Name `xml:"w:rFonts"`
+	Ascii   string   `xml:"w:ascii,attr,omitempty"`
+	HAnsi   string   `xml:"w:hAnsi,attr,omitempty"`
+}
+
+type Text struct {
+	XMLName xml.Name `xml:"w:t"`
+	Space   string   `xml:"xml:space,attr,omitempty"`
+	Value   string   `xml:",chardata"`
+}
+
+type Bold struct {
+	XMLName xml.Name `xml:"w:b"`
+	Val     string   `xml:"w:val,attr,omitempty"` // "0" ปิดตัวหนา/ตัวเอียงที่มาจาก style
+}
+
+type Italic struct {
+	XMLName xml.Name `xml:"w:i"`
+	Val     string   `xml:"w:val,attr,omitempty"` // "0" ปิดตัวหนา/ตัวเอียงที่มาจาก style
+}
+
+type BoldCs struct {
+	XMLName xml.Name `xml:"w:bCs"`
+	Val     string   `xml:"w:val,attr,omitempty"`
+}
+
+type ItalicCs struct {
+	XMLName xml.Name `xml:"w:iCs"`
+	Val     string   `xml:"w:val,attr,omitempty"`
+}
+
+type Caps struct {
+	XMLName xml.Name `xml:"w:caps"`
+}
+
+type SmallCaps struct {
+	XMLName xml.Name `xml:"w:smallCaps"`
+}
+
+type Strike struct {
+	XMLName xml.Name `xml:"w:strike"`
+}
+
+// DStrike คือขีดฆ่าสองเส้น
+type DStrike struct {
+	XMLName xml.Name `xml:"w:dstrike"`
+}
+
+// Highlight ใช้ได้เฉพาะสีที่ตั้งชื่อไว้ของ Word (yellow, green, cyan, ...)
+type Highlight struct {
+	XMLName xml.Name `xml:"w:highlight"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// Underline คือรูปแบบขีดเส้นใต้ (single, double, dotted, dash, wave หรือ none)
+type Underline struct {
+	XMLName xml.Name `xml:"w:u"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// Shd คือสีพื้นหลังแบบใดก็ได้ (Fill เป็น hex)
+type Shd struct {
+	XMLName xml.Name `xml:"w:shd"`
+	Val     string   `xml:"w:val,attr"`
+	Color   string   `xml:"w:color,attr,omitempty"`
+	Fill    string   `xml:"w:fill,attr"`
+}
+
+// VertAlign คือตัวยก (superscript) หรือตัวห้อย (subscript)
+type VertAlign struct {
+	XMLName xml.Name `xml:"w:vertAlign"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+type Color struct {
+	XMLName xml.Name `xml:"w:color"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+type Size struct {
+	XMLName xml.Name `xml:"w:sz"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+type SizeCs struct {
+	XMLName xml.Name `xml:"w:szCs"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+type Break struct {
+	XMLName xml.Name `xml:"w:br"`
+	Type    string   `xml:"w:type,attr,omitempty"`
+}
+
+type Jc struct {
+	XMLName xml.Name `xml:"w:jc"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+type Spacing struct {
+	XMLName  xml.Name `xml:"w:spacing"`
+	Before   string   `xml:"w:before,attr,omitempty"`
+	After    string   `xml:"w:after,attr,omitempty"`
+	Line     string   `xml:"w:line,attr,omitempty"`
+	LineRule string   `xml:"w:lineRule,attr,omitempty"` // auto = 240 ต่อบรรทัด, exact = twips
+}
+
+type Ind struct {
+	XMLName   xml.Name `xml:"w:ind"`
+	Left      string   `xml:"w:left,attr,omitempty"`
+	Right     string   `xml:"w:right,attr,omitempty"`
+	FirstLine string   `xml:"w:firstLine,attr,omitempty"`
+	Hanging   string   `xml:"w:hanging,attr,omitempty"`
+}
+
+type PStyle struct {
+	XMLName xml.Name `xml:"w:pStyle"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// NumPr ทำให้ paragraph เป็นรายการใน list ตาม numbering.xml
+type NumPr struct {
+	XMLName xml.Name `xml:"w:numPr"`
+	Ilvl    *Ilvl    `xml:"w:ilvl"`
+	NumId   *NumId   `xml:"w:numId"`
+}
+
+type Ilvl struct {
+	XMLName xml.Name `xml:"w:ilvl"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+type NumId struct {
+	XMLName xml.Name `xml:"w:numId"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// RStyle อ้างถึง character style เช่น Strong หรือ Emphasis
+type RStyle struct {
+	XMLName xml.Name `xml:"w:rStyle"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+type OutlineLvl struct {
+	XMLName xml.Name `xml:"w:outlineLvl"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+type Tabs struct {
+	XMLName xml.Name  `xml:"w:tabs"`
+	Tabs    []TabStop `xml:"w:tab"`
+}
+
+type TabStop struct {
+	XMLName xml.Name `xml:"w:tab"`
+	Val     string   `xml:"w:val,attr"`
+	Leader  string   `xml:"w:leader,attr,omitempty"`
+	Pos     string   `xml:"w:pos,attr"`
+}
+
+type Tab struct {
+	XMLName xml.Name `xml:"w:tab"`
+}
+
+// FldChar และ InstrText ใช้สร้าง complex field (TOC, PAGEREF, ...)
+type FldChar struct {
+	XMLName     xml.Name `xml:"w:fldChar"`
+	FldCharType string   `xml:"w:fldCharType,attr"`
+	Dirty       string   `xml:"w:dirty,attr,omitempty"`
+}
+
+type InstrText struct {
+	XMLName xml.Name `xml:"w:instrText"`
+	Space   string   `xml:"xml:space,attr,omitempty"`
+	Value   string   `xml:",chardata"`
+}
+
+type BookmarkStart struct {
+	XMLName xml.Name `xml:"w:bookmarkStart"`
+	Id      string   `xml:"w:id,attr"`
+	Name    string   `xml:"w:name,attr"`
+}
+
+type BookmarkEnd struct {
+	XMLName xml.Name `xml:"w:bookmarkEnd"`
+	Id      string   `xml:"w:id,attr"`
+}
+
+// Hyperlink ชี้ไปที่ bookmark (Anchor) หรือ URL ภายนอกผ่าน relationship (RelId)
+type Hyperlink struct {
+	XMLName xml.Name `xml:"w:hyperlink"`
+	RelId   string   `xml:"r:id,attr,omitempty"`
+	Anchor  string   `xml:"w:anchor,attr,omitempty"`
+	History string   `xml:"w:history,attr,omitempty"`
+	Runs    []Run    `xml:"w:r"`
+}
+
+// Table คือตาราง (w:tbl) ใน body หรือในเซลล์ของตารางอื่น
+type Table struct {
+	XMLName xml.Name   `xml:"w:tbl"`
+	Props   TblPr      `xml:"w:tblPr"`
+	Grid    TblGrid    `xml:"w:tblGrid"`
+	Rows    []TableRow `xml:"w:tr"`
+}
+
+// ลำดับ field ของ TblPr ต้องตรงกับลำดับใน schema ของ w:tblPr
+type TblPr struct {
+	XMLName    xml.Name   `xml:"w:tblPr"`
+	TblStyle   *TblStyle  `xml:"w:tblStyle,omitempty"`
+	TblW       *TblWidth  `xml:"w:tblW,omitempty"`
+	TblBorders *Borders   `xml:"w:tblBorders,omitempty"`
+	TblLayout  *TblLayout `xml:"w:tblLayout,omitempty"`
+}
+
+type TblStyle struct {
+	XMLName xml.Name `xml:"w:tblStyle"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// TblWidth ใช้ได้ทั้ง w:tblW และ w:tcW (ชื่อ element มาจาก tag ของ field)
+type TblWidth struct {
+	W    string `xml:"w:w,attr"`
+	Type string `xml:"w:type,attr"`
+}
+
+type TblLayout struct {
+	XMLName xml.Name `xml:"w:tblLayout"`
+	Type    string   `xml:"w:type,attr"`
+}
+
+type TblGrid struct {
+	XMLName xml.Name  `xml:"w:tblGrid"`
+	Cols    []GridCol `xml:"w:gridCol"`
+}
+
+type GridCol struct {
+	XMLName xml.Name `xml:"w:gridCol"`
+	W       string   `xml:"w:w,attr"`
+}
+
+type TableRow struct {
+	XMLName xml.Name    `xml:"w:tr"`
+	Props   *TrPr       `xml:"w:trPr,omitempty"`
+	Cells   []TableCell `xml:"w:tc"`
+}
+
+// TrPr ของแถวหัวตาราง (ทำซ้ำที่ด้านบนของทุกหน้า)
+type TrPr struct {
+	XMLName   xml.Name   `xml:"w:trPr"`
+	CantSplit *CantSplit `xml:"w:cantSplit,omitempty"`
+	TblHeader *TblHeader `xml:"w:tblHeader,omitempty"`
+}
+
+type CantSplit struct {
+	XMLName xml.Name `xml:"w:cantSplit"`
+}
+
+type TblHeader struct {
+	XMLName xml.Name `xml:"w:tblHeader"`
+}
+
+// TableCell มี paragraph หรือตารางซ้อน (element สุดท้ายต้องเป็น paragraph)
+type TableCell struct {
+	XMLName xml.Name      `xml:"w:tc"`
+	Props   TcPr          `xml:"w:tcPr"`
+	Content []interface{} `xml:",any"`
+}
+
+// ลำดับ field ของ TcPr ต้องตรงกับลำดับใน schema ของ w:tcPr
+type TcPr struct {
+	XMLName   xml.Name  `xml:"w:tcPr"`
+	TcW       *TblWidth `xml:"w:tcW,omitempty"`
+	GridSpan  *GridSpan `xml:"w:gridSpan,omitempty"`
+	VMerge    *VMerge   `xml:"w:vMerge,omitempty"`
+	TcBorders *Borders  `xml:"w:tcBorders,omitempty"`
+}
+
+type GridSpan struct {
+	XMLName xml.Name `xml:"w:gridSpan"`
+	Val     string   `xml:"w:val,attr"`
+}
+
+// VMerge รวมเซลล์ในแนวตั้ง: Val "restart" คือเซลล์แรก ค่าว่างคือเซลล์ที่ต่อจากด้านบน
+type VMerge struct {
+	XMLName xml.Name `xml:"w:vMerge"`
+	Val     string   `xml:"w:val,attr,omitempty"`
+}
+
+// Borders ใช้ได้ทั้ง w:tblBorders และ w:tcBorders ตามลำดับใน schema
+type Borders struct {
+	Top     *Border `xml:"w:top,omitempty"`
+	Left    *Border `xml:"w:left,omitempty"`
+	Bottom  *Border `xml:"w:bottom,omitempty"`
+	Right   *Border `xml:"w:right,omitempty"`
+	InsideH *Border `xml:"w:insideH,omitempty"`
+	InsideV *Border `xml:"w:insideV,omitempty"`
+}
+
+// Border คือเส้นขอบหนึ่งด้าน (Sz เป็น 1/8 pt)
+type Border struct {
+	Val   string `xml:"w:val,attr"`
+	Sz    string `xml:"w:sz,attr,omitempty"`
+	Space string `xml:"w:space,attr,omitempty"`
+	Color string `xml:"w:color,attr,omitempty"`
+}
+
+// โครงสร้างสำหรับ relationships
+type Relationship struct {
+	Id         string `xml:"Id,attr"`
+	Type       string `xml:"Type,attr"`
+	Target     string `xml:"Target,attr"`
+	TargetMode string `xml:"TargetMode,attr,omitempty"`
+}
+
+type Relationships struct {
+	XMLName xml.Name       `xml:"Relationships"`
+	Xmlns   string         `xml:"xmlns,attr"`
+	Items   []Relationship `xml:"Relationship"`
+}
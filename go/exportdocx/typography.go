@@ -0,0 +1,119 @@
+package exportdocx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+)
+
+// Typography กำหนดฟอนต์ ขนาด และภาษาเริ่มต้นของเอกสาร (docDefaults ใน styles.xml)
+// Word เลือกฟอนต์ตามชนิดของตัวอักษร: ละติน (ascii/hAnsi), เอเชียตะวันออก (eastAsia)
+// และ complex script (cs) ซึ่งรวมภาษาไทย ข้อความไทยจึงใช้ ComplexFont และ szCs
+type Typography struct {
+	LatinFont    string // w:ascii และ w:hAnsi
+	EastAsiaFont string // w:eastAsia
+	ComplexFont  string // w:cs
+	// FontSize ขนาดตัวอักษรเป็นครึ่ง point (w:sz และ w:szCs) เช่น 32 = 16pt
+	FontSize int
+
+	Lang     string // w:lang w:val ภาษาของข้อความละติน เช่น en-US
+	BidiLang string // w:lang w:bidi ภาษาของ complex script เช่น th-TH
+
+	// Justification จัดแนวย่อหน้าเนื้อหา (style BodyText) ย่อหน้าที่กำหนด text-align ใช้ค่านั้นแทน
+	// ("" = ชิดซ้าย, "both" = เต็มบรรทัด, "thaiDistribute" = กระจายแบบไทย)
+	Justification string
+}
+
+// DefaultTypography คือค่าเดิมของ converter (Times New Roman 11pt)
+func DefaultTypography() Typography {
+	return Typography{
+		LatinFont:    "Times New Roman",
+		EastAsiaFont: "TH SarabunPSK",
+		ComplexFont:  "Times New Roman",
+		FontSize:     22,
+	}
+}
+
+// ThaiTypography ใช้ TH Sarabun New กับทุกชนิดตัวอักษร ขนาด 16pt
+// ตั้งภาษา complex script เป็น th-TH และจัดย่อหน้าแบบ thaiDistribute
+func ThaiTypography() Typography {
+	return Typography{
+		LatinFont:     "TH Sarabun New",
+		EastAsiaFont:  "TH Sarabun New",
+		ComplexFont:   "TH Sarabun New",
+		FontSize:      32,
+		Lang:          "en-US",
+		BidiLang:      "th-TH",
+		Justification: "thaiDistribute",
+	}
+}
+
+// typography คืน Typography ของ Options โดยเติมค่าที่ว่างจาก DefaultTypography
+func (e *Exporter) typography() Typography {
+	typo := e.opts.Typography
+	defaults := DefaultTypography()
+	if typo.LatinFont == "" {
+		typo.LatinFont = defaults.LatinFont
+	}
+	if typo.EastAsiaFont == "" {
+		typo.EastAsiaFont = defaults.EastAsiaFont
+	}
+	if typo.ComplexFont == "" {
+		typo.ComplexFont = defaults.ComplexFont
+	}
+	if typo.FontSize <= 0 {
+		typo.FontSize = defaults.FontSize
+	}
+	return typo
+}
+
+// rPrDefault คืน w:rPr ของ docDefaults ตาม Typography
+func (t Typography) rPrDefault() string {
+	var buf bytes.Buffer
+	attr := func(name, value string) {
+		if value == "" {
+			return
+		}
+		buf.WriteString("\n          " + name + `="`)
+		xml.EscapeText(&buf, []byte(value))
+		buf.WriteString(`"`)
+	}
+
+	buf.WriteString("<w:rPr>\n        <w:rFonts")
+	attr("w:ascii", t.LatinFont)
+	attr("w:hAnsi", t.LatinFont)
+	attr("w:cs", t.ComplexFont)
+	attr("w:eastAsia", t.EastAsiaFont)
+	buf.WriteString("/>")
+
+	size := strconv.Itoa(t.FontSize)
+	buf.WriteString("\n        <w:sz w:val=\"" + size + "\"/>")
+	buf.WriteString("\n        <w:szCs w:val=\"" + size + "\"/>")
+
+	if t.Lang != "" || t.BidiLang != "" {
+		buf.WriteString("\n        <w:lang")
+		attr("w:val", t.Lang)
+		attr("w:bidi", t.BidiLang)
+		buf.WriteString("/>")
+	}
+	buf.WriteString("\n      </w:rPr>")
+	return buf.String()
+}
+
+// mirrorComplexScript ใส่ bCs, iCs และ szCs ให้ตรงกับ b, i และ sz
+// เพราะ Word ใช้ค่าฝั่ง complex script กับข้อความไทย
+func (p *RPr) mirrorComplexScript() *RPr {
+	if p == nil {
+		return nil
+	}
+	if p.Bold != nil {
+		p.BoldCs = &BoldCs{Val: p.Bold.Val}
+	}
+	if p.Italic != nil {
+		p.ItalicCs = &ItalicCs{Val: p.Italic.Val}
+	}
+	if p.Size != nil {
+		p.SizeCs = &SizeCs{Val: p.Size.Val}
+	}
+	return p
+}
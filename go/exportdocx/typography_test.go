@@ -0,0 +1,73 @@
+package exportdocx
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestExportThaiTypography(t *testing.T) {
+	chapters := []ChapterData{{ID: "1", Chapter: "บทที่ 1", Body: `<p>ข้อความ <b>หนา</b> <span style="font-size: 18pt">ใหญ่</span></p><p style="text-align: center">กลาง</p>`}}
+
+	var buf bytes.Buffer
+	if err := Export(context.Background(), chapters, &buf, Options{Typography: ThaiTypography()}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	files := readZipFiles(t, buf.Bytes())
+
+	styles := compactXML(files["word/styles.xml"])
+	for _, want := range []string{
+		`w:cs="THSarabunNew"`,
+		`w:ascii="THSarabunNew"`,
+		`<w:szCsw:val="32"/>`,
+		`<w:langw:val="en-US"w:bidi="th-TH"/>`,
+		`<w:spacingw:after="120"/><w:jcw:val="thaiDistribute"/>`,
+		// ชื่อบทภาษาไทยต้องหนาและโค้ดภาษาไทยต้องเล็กลงด้วย
+		`w:styleId="Heading1"><w:namew:val="heading1"/>`,
+		`<w:outlineLvlw:val="0"/></w:pPr><w:rPr><w:b/><w:bCs/><w:szw:val="32"/><w:szCsw:val="32"/>`,
+		`<w:linkw:val="Heading1"/><w:uiPriorityw:val="9"/><w:rPr><w:b/><w:bCs/>`,
+		`w:hAnsi="CourierNew"/><w:szw:val="20"/><w:szCsw:val="20"/></w:rPr></w:style><w:stylew:type="character"w:styleId="HTMLCode">`,
+		`w:hAnsi="CourierNew"/><w:szw:val="20"/><w:szCsw:val="20"/></w:rPr></w:style><w:stylew:type="paragraph"w:styleId="Caption">`,
+	} {
+		if !strings.Contains(styles, want) {
+			t.Errorf("styles.xml missing %s", want)
+		}
+	}
+
+	doc := compactXML(files["word/document.xml"])
+	for _, want := range []string{
+		`<w:rStylew:val="Strong"></w:rStyle>`,
+		`<w:szw:val="36"></w:sz><w:szCsw:val="36"></w:szCs>`,
+		`<w:jcw:val="center">`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("document.xml missing %q", want)
+		}
+	}
+	// การจัดแนวมาจาก style BodyText ไม่ใส่ซ้ำในแต่ละย่อหน้า
+	if strings.Contains(doc, "thaiDistribute") {
+		t.Errorf("thaiDistribute should come from the BodyText style")
+	}
+}
+
+func TestDefaultTypographyKeepsOriginalFonts(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Export(context.Background(), nil, &buf, Options{}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	styles := readZipFiles(t, buf.Bytes())["word/styles.xml"]
+	for _, want := range []string{`w:ascii="Times New Roman"`, `w:eastAsia="TH SarabunPSK"`, `<w:sz w:val="22"/>`} {
+		if !strings.Contains(styles, want) {
+			t.Errorf("styles.xml missing %s", want)
+		}
+	}
+	if strings.Contains(styles, "<w:lang") {
+		t.Error("default typography should not set w:lang")
+	}
+}
+
+// compactXML ตัด whitespace ทั้งหมดเพื่อเทียบ XML โดยไม่ขึ้นกับการจัดย่อหน้า
+func compactXML(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
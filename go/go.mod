@@ -2,4 +2,7 @@ module go-export-docx
 
 go 1.24.4
 
-require golang.org/x/net v0.41.0
+require (
+	golang.org/x/image v0.32.0
+	golang.org/x/net v0.41.0
+)
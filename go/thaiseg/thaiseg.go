@@ -0,0 +1,248 @@
+// Package thaiseg ตัดคำภาษาไทยด้วยพจนานุกรมแบบ maximal matching
+//
+// ภาษาไทยไม่เว้นวรรคระหว่างคำ โปรแกรมที่ไม่มีตัวตัดคำไทย (เช่น LibreOffice บางรุ่น
+// หรือโปรแกรมอ่านบนมือถือ) จึงอาจตัดบรรทัดกลางคำ InsertBreaks ใส่ zero-width space
+// (U+200B) ระหว่างคำเพื่อบอกจุดที่ตัดบรรทัดได้ โดยไม่เปลี่ยนสิ่งที่แสดงบนหน้าจอ
+package thaiseg
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ZeroWidthSpace คือตัวคั่นที่ InsertBreaks ใส่ระหว่างคำ
+const ZeroWidthSpace = "\u200B"
+
+//go:embed words.txt
+var defaultWords string
+
+var (
+	defaultOnce      sync.Once
+	defaultSegmenter *Segmenter
+)
+
+// Segmenter ตัดคำด้วยพจนานุกรม ใช้พร้อมกันหลาย goroutine ได้
+type Segmenter struct {
+	root *trieNode
+}
+
+type trieNode struct {
+	children map[rune]*trieNode
+	word     bool
+}
+
+// New สร้าง Segmenter จากรายการคำ (คำที่ไม่มีอักษรไทยจะถูกข้าม)
+func New(words []string) *Segmenter {
+	s := &Segmenter{root: &trieNode{}}
+	for _, word := range words {
+		s.add(strings.TrimSpace(word))
+	}
+	return s
+}
+
+// Default คืน Segmenter ที่ใช้พจนานุกรมที่ฝังมากับ package
+func Default() *Segmenter {
+	defaultOnce.Do(func() {
+		var words []string
+		for _, line := range strings.Split(defaultWords, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			words = append(words, line)
+		}
+		defaultSegmenter = New(words)
+	})
+	return defaultSegmenter
+}
+
+func (s *Segmenter) add(word string) {
+	if word == "" || !strings.ContainsFunc(word, isThai) {
+		return
+	}
+	node := s.root
+	for _, r := range word {
+		if node.children == nil {
+			node.children = make(map[rune]*trieNode)
+		}
+		next, ok := node.children[r]
+		if !ok {
+			next = &trieNode{}
+			node.children[r] = next
+		}
+		node = next
+	}
+	node.word = true
+}
+
+// Segment แบ่งข้อความเป็นคำ ข้อความที่ไม่ใช่อักษรไทย (ละติน ตัวเลข ช่องว่าง เครื่องหมาย)
+// เป็นหนึ่งส่วนต่อช่วง และนำทุกส่วนมาต่อกันได้ข้อความเดิมเสมอ
+func (s *Segmenter) Segment(text string) []string {
+	var segments []string
+	for len(text) > 0 {
+		end := indexFunc(text, isThai, false)
+		if end == 0 {
+			end = indexFunc(text, isThai, true)
+			segments = append(segments, s.segmentThai(text[:end])...)
+		} else {
+			segments = append(segments, text[:end])
+		}
+		text = text[end:]
+	}
+	return segments
+}
+
+// InsertBreaks ใส่ ZeroWidthSpace ระหว่างคำไทยที่อยู่ติดกัน
+// ตำแหน่งที่ติดกับช่องว่างหรืออักษรอื่นอยู่แล้วจะไม่ถูกเปลี่ยน
+func (s *Segmenter) InsertBreaks(text string) string {
+	if !strings.ContainsFunc(text, isThai) {
+		return text
+	}
+
+	segments := s.Segment(text)
+	var b strings.Builder
+	b.Grow(len(text) + len(segments)*len(ZeroWidthSpace))
+	for i, segment := range segments {
+		if i > 0 && isThaiSegment(segments[i-1]) && isThaiSegment(segment) {
+			b.WriteString(ZeroWidthSpace)
+		}
+		b.WriteString(segment)
+	}
+	return b.String()
+}
+
+// segmentThai ตัดข้อความที่เป็นอักษรไทยล้วนด้วย dynamic programming
+// เลือกวิธีตัดที่มีส่วนที่ไม่รู้จักน้อยที่สุด แล้วจึงเลือกที่มีจำนวนคำน้อยที่สุด (maximal matching)
+// ส่วนที่ไม่อยู่ในพจนานุกรมจะตัดตามขอบของกลุ่มอักษร (ไม่แยกสระหรือวรรณยุกต์ออกจากพยัญชนะ)
+func (s *Segmenter) segmentThai(text string) []string {
+	runes := []rune(text)
+	n := len(runes)
+
+	type state struct {
+		unknown, words int
+		prev           int
+		known          bool
+		reached        bool
+	}
+	best := make([]state, n+1)
+	best[0].reached = true
+
+	better := func(a, b state) bool {
+		if !b.reached {
+			return true
+		}
+		if a.unknown != b.unknown {
+			return a.unknown < b.unknown
+		}
+		return a.words < b.words
+	}
+
+	for i := 0; i < n; i++ {
+		if !best[i].reached || !canBreakBefore(runes, i) {
+			continue
+		}
+		cur := best[i]
+
+		// คำในพจนานุกรมที่เริ่มที่ตำแหน่ง i
+		node := s.root
+		for j := i; j < n; j++ {
+			node = node.children[runes[j]]
+			if node == nil {
+				break
+			}
+			if node.word && canBreakBefore(runes, j+1) {
+				next := state{unknown: cur.unknown, words: cur.words + 1, prev: i, known: true, reached: true}
+				if better(next, best[j+1]) {
+					best[j+1] = next
+				}
+			}
+		}
+
+		// ข้ามไปหนึ่งกลุ่มอักษรเมื่อไม่พบคำ
+		j := i + 1
+		for j < n && !canBreakBefore(runes, j) {
+			j++
+		}
+		next := state{unknown: cur.unknown + 1, words: cur.words + 1, prev: i, reached: true}
+		if better(next, best[j]) {
+			best[j] = next
+		}
+	}
+
+	// ย้อนเส้นทาง แล้วรวมส่วนที่ไม่รู้จักที่อยู่ติดกันเป็นส่วนเดียว
+	var bounds []int
+	var known []bool
+	for end := n; end > 0; end = best[end].prev {
+		bounds = append(bounds, end)
+		known = append(known, best[end].known)
+	}
+
+	var segments []string
+	start := 0
+	for k := len(bounds) - 1; k >= 0; k-- {
+		end := bounds[k]
+		if !known[k] && k > 0 && !known[k-1] {
+			continue
+		}
+		segments = append(segments, string(runes[start:end]))
+		start = end
+	}
+	return segments
+}
+
+// canBreakBefore คืน true ถ้าตัดคำก่อนตำแหน่ง i ได้โดยไม่แยกกลุ่มอักษร
+func canBreakBefore(runes []rune, i int) bool {
+	if i <= 0 || i >= len(runes) {
+		return true
+	}
+	r, prev := runes[i], runes[i-1]
+	switch {
+	case isFollowingMark(r):
+		// สระบน สระล่าง วรรณยุกต์ และสระที่ตามพยัญชนะ ต้องอยู่กับอักษรก่อนหน้า
+		return false
+	case isLeadingVowel(prev):
+		// สระหน้า (เ แ โ ใ ไ) ต้องอยู่กับพยัญชนะที่ตามมา
+		return false
+	}
+	return true
+}
+
+// สระและเครื่องหมายที่เขียนตามหลังหรือซ้อนบนล่างพยัญชนะ
+func isFollowingMark(r rune) bool {
+	switch {
+	case r == 'ะ', r == 'ั', r == 'า', r == 'ำ':
+		return true
+	case r >= 'ิ' && r <= 'ฺ': // ิ ี ึ ื ุ ู ฺ
+		return true
+	case r >= '็' && r <= '๎': // ็ ่ ้ ๊ ๋ ์ ํ ๎
+		return true
+	case r == 'ๅ', r == 'ๆ':
+		return true
+	}
+	return false
+}
+
+func isLeadingVowel(r rune) bool {
+	return r >= 'เ' && r <= 'ไ'
+}
+
+func isThai(r rune) bool {
+	return r >= 0x0E01 && r <= 0x0E5B
+}
+
+func isThaiSegment(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return isThai(r)
+}
+
+// indexFunc คืนตำแหน่งแรกที่ f(r) != want หรือความยาวของ s ถ้าไม่พบ
+func indexFunc(s string, f func(rune) bool, want bool) int {
+	for i, r := range s {
+		if f(r) != want {
+			return i
+		}
+	}
+	return len(s)
+}
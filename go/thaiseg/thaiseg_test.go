@@ -0,0 +1,89 @@
+package thaiseg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSegmentMaximalMatching(t *testing.T) {
+	s := New([]string{"ตา", "กลม", "ตาก", "ลม", "ไป", "ทำงาน", "ทำ", "งาน"})
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		// "ตากลม" ตัดเป็น ตา|กลม หรือ ตาก|ลม ก็ได้ทั้งคู่ (2 คำ) แต่ต้องไม่เหลือส่วนที่ไม่รู้จัก
+		{"ไปทำงาน", []string{"ไป", "ทำงาน"}},
+		{"ทำงาน ไป", []string{"ทำงาน", " ", "ไป"}},
+		{"go ทำงาน 2 วัน", []string{"go ", "ทำงาน", " 2 ", "วัน"}},
+	}
+	for _, tt := range tests {
+		got := s.Segment(tt.text)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Segment(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+
+	if got := s.Segment("ตากลม"); len(got) != 2 {
+		t.Errorf("Segment(ตากลม) = %q, want 2 words", got)
+	}
+}
+
+func TestSegmentUnknownKeepsClusters(t *testing.T) {
+	s := New([]string{"ไป"})
+
+	// คำที่ไม่อยู่ในพจนานุกรมต้องไม่ถูกแยกสระหรือวรรณยุกต์ออกจากพยัญชนะ
+	// และส่วนที่ไม่รู้จักที่อยู่ติดกันถูกรวมเป็นส่วนเดียว
+	got := s.Segment("ไปเที่ยวไป")
+	want := []string{"ไป", "เที่ยว", "ไป"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Segment = %q, want %q", got, want)
+	}
+}
+
+func TestSegmentRoundTrip(t *testing.T) {
+	texts := []string{
+		"",
+		"hello world",
+		"ภาษาไทยไม่เว้นวรรคระหว่างคำ",
+		"ราคา 120 บาท (รวมภาษี) ครับ",
+		"กขคงจฉ",
+		"เเเ",
+	}
+	for _, text := range texts {
+		if got := strings.Join(Default().Segment(text), ""); got != text {
+			t.Errorf("Segment(%q) joined = %q", text, got)
+		}
+	}
+}
+
+func TestInsertBreaks(t *testing.T) {
+	s := New([]string{"ภาษา", "ไทย", "ง่าย"})
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"ภาษาไทยง่าย", "ภาษา" + ZeroWidthSpace + "ไทย" + ZeroWidthSpace + "ง่าย"},
+		// ไม่ใส่ซ้ำเมื่อมีช่องว่างหรืออักษรอื่นคั่นอยู่แล้ว
+		{"ภาษา ไทย", "ภาษา ไทย"},
+		{"ภาษาThaiไทย", "ภาษาThaiไทย"},
+		{"plain text", "plain text"},
+	}
+	for _, tt := range tests {
+		if got := s.InsertBreaks(tt.text); got != tt.want {
+			t.Errorf("InsertBreaks(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultDictionary(t *testing.T) {
+	got := Default().InsertBreaks("ภาษาไทยไม่เว้นวรรค")
+	if strings.Count(got, ZeroWidthSpace) < 2 {
+		t.Errorf("InsertBreaks with default dictionary = %q, want word breaks", got)
+	}
+	if strings.ReplaceAll(got, ZeroWidthSpace, "") != "ภาษาไทยไม่เว้นวรรค" {
+		t.Errorf("InsertBreaks changed text: %q", got)
+	}
+}